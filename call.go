@@ -3,8 +3,11 @@ package vermock
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Callable defines an interface for delegates to call test functions.
@@ -69,14 +72,36 @@ type Value struct {
 
 // Call invokes the Callable with the given arguments.  If the Callable is variadic,
 // the last argument must be passed as a slice, otherwise this method panics.
+// A Callable that is not variadic may omit the last argument when it is a
+// slice, that is, when the Callable accepts exactly one fewer argument than
+// given, not counting an optional leading testing.TB or *testing.T.  This
+// allows a delegate of a variadic method to ignore the variadic arguments;
+// CallDelegate fails the call of a method that is not variadic instead.
+// If the first argument of the Callable, after an optional testing.TB or
+// *testing.T, is of type CallCount, then it is passed the call count i,
+// whether the Callable was registered with Expect or ExpectMany and whether
+// or not it has results, so that it may count its calls.  An
+// invalid argument, such as that of a nil interface, is passed as the zero
+// value of the parameter.
 func (v Value) Call(t testing.TB, i CallCount, in []reflect.Value) []reflect.Value {
 	fn := v.Value
 	if fn.Kind() != reflect.Func {
 		panic(fmt.Sprintf("Value.Call: expected func, got %T", v))
 	}
+	if missesVariadicArg(fn.Type(), in) {
+		in = append(in, reflect.MakeSlice(fn.Type().In(fn.Type().NumIn()-1), 0, 0))
+	}
+	if funcType := fn.Type(); funcType.NumIn() > 0 && funcType.In(0) == callCountType ||
+		funcType.NumIn() > 1 && funcType.In(1) == callCountType {
+		in = append([]reflect.Value{reflect.ValueOf(i)}, in...)
+	}
+	if omitsLastArg(fn.Type(), in) {
+		in = in[:len(in)-1]
+	}
 	if fn.Type().NumIn() == len(in)+1 {
 		in = append([]reflect.Value{reflect.ValueOf(t)}, in...)
 	}
+	in = zeroNilArgs(fn.Type(), in)
 	if fn.Type().IsVariadic() {
 		return fn.CallSlice(in)
 	} else {
@@ -84,6 +109,104 @@ func (v Value) Call(t testing.TB, i CallCount, in []reflect.Value) []reflect.Val
 	}
 }
 
+var (
+	// tbType is the type of the testing.TB interface.
+	tbType = reflect.TypeOf((*testing.TB)(nil)).Elem()
+	// tType is the type of *testing.T.
+	tType = reflect.TypeOf((*testing.T)(nil))
+	// callCountType is the type of CallCount.
+	callCountType = reflect.TypeOf(CallCount(0))
+)
+
+// omitsLastArg reports whether a function of type funcType ignores the last
+// of the given arguments, which must be a slice.
+func omitsLastArg(funcType reflect.Type, in []reflect.Value) bool {
+	if funcType.IsVariadic() || len(in) == 0 || in[len(in)-1].Kind() != reflect.Slice {
+		return false
+	}
+	n := funcType.NumIn()
+	if n > 0 && (funcType.In(0) == tbType || funcType.In(0) == tType) {
+		n--
+	}
+	return n == len(in)-1
+}
+
+// omittedArgErr returns an error if the given Callable would omit the last of
+// the given arguments, as described by Value.Call, for a method of the mock
+// with the given name that is not variadic.
+func omittedArgErr(mock *mock, name string, callable Callable, in []reflect.Value) error {
+	funcType, ok := callableType(callable)
+	if !ok || funcType.IsVariadic() || len(in) == 0 || in[len(in)-1].Kind() != reflect.Slice {
+		return nil
+	}
+	n := funcType.NumIn()
+	if n > 0 && (funcType.In(0) == tbType || funcType.In(0) == tType) {
+		n--
+	}
+	if n > 0 && funcType.In(funcType.NumIn()-n) == callCountType {
+		n--
+	}
+	if n != len(in)-1 || mock.variadic(name) {
+		return nil
+	}
+	return fmt.Errorf("delegate of %s omits its last argument, but %s is not variadic: %s", name, name, funcType)
+}
+
+// variadic reports whether the method of the mock with the given name is
+// variadic.  A method that is not known, such as one of a mock that was not
+// created by New, is taken to be variadic.
+func (m *mock) variadic(name string) bool {
+	if m.keyType == nil {
+		return true
+	}
+	if name == FuncName && m.keyType.Elem().Kind() == reflect.Func {
+		return m.keyType.Elem().IsVariadic()
+	}
+	method, ok := m.keyType.MethodByName(name)
+	return !ok || method.Type.IsVariadic()
+}
+
+// missesVariadicArg reports whether a variadic function of type funcType is
+// given no slice for its variadic argument, that is, whether it accepts
+// exactly one more argument than given, not counting an optional leading
+// testing.TB or *testing.T and CallCount.  A mock method that spreads its
+// variadic arguments, rather than passing them as a slice, passes no slice
+// when called with no variadic arguments.
+func missesVariadicArg(funcType reflect.Type, in []reflect.Value) bool {
+	if !funcType.IsVariadic() {
+		return false
+	}
+	n := funcType.NumIn()
+	if n > 1 && (funcType.In(0) == tbType || funcType.In(0) == tType) {
+		n--
+	}
+	if n > 1 && funcType.In(funcType.NumIn()-n) == callCountType {
+		n--
+	}
+	return n == len(in)+1
+}
+
+// zeroNilArgs returns the given arguments with each invalid argument, which
+// is the reflect.Value of an untyped nil, such as a nil interface passed to
+// one of the CallN functions, replaced by the zero value of the corresponding
+// parameter of a function of type funcType.  The given slice is not modified.
+func zeroNilArgs(funcType reflect.Type, in []reflect.Value) []reflect.Value {
+	var out []reflect.Value
+	for i, arg := range in {
+		if arg.IsValid() || i >= funcType.NumIn() {
+			continue
+		}
+		if out == nil {
+			out = append([]reflect.Value(nil), in...)
+		}
+		out[i] = reflect.Zero(funcType.In(i))
+	}
+	if out == nil {
+		return in
+	}
+	return out
+}
+
 // multi is a Callable that wraps a reflect.Value and implements MultiCallable.
 type multi Value
 
@@ -92,73 +215,351 @@ func (v multi) MultiCallable() bool { return true }
 
 // Call invokes the Callable with the given arguments.
 func (v multi) Call(t testing.TB, i CallCount, in []reflect.Value) []reflect.Value {
-	funcType := v.Value.Type()
-	if funcType.NumIn() > 0 && funcType.In(0) == reflect.TypeOf(i) ||
-		funcType.NumIn() > 1 && funcType.In(1) == reflect.TypeOf(i) {
-		in = append([]reflect.Value{reflect.ValueOf(i)}, in...)
-	}
 	return Value(v).Call(t, i, in)
 }
 
+// bounded is a MultiCallable that is expected to be called at least min and
+// at most max times.
+type bounded struct {
+	multi
+	min, max int
+}
+
+// minCalls returns the number of calls needed to satisfy all Callables: one
+// for each Callable, except that a bounded Callable at the end needs its
+// minimum and a spy at the end needs none.
+func (c Callables) minCalls() int {
+	switch last := c.last().(type) {
+	case bounded:
+		return len(c) - 1 + last.min
+	case *spy:
+		return len(c) - 1
+	}
+	return len(c)
+}
+
+// maxCalls returns the number of calls allowed by the Callables when the last
+// is bounded, otherwise false.  The result saturates at math.MaxInt.
+func (c Callables) maxCalls() (int, bool) {
+	if b, ok := c.last().(bounded); ok {
+		if b.max > math.MaxInt-(len(c)-1) {
+			return math.MaxInt, true
+		}
+		return len(c) - 1 + b.max, true
+	}
+	return 0, false
+}
+
+// last returns the last Callable or nil if there is none.
+func (c Callables) last() Callable {
+	if len(c) == 0 {
+		return nil
+	}
+	return c[len(c)-1]
+}
+
 // errType is the type of the error interface.
 var errType = reflect.TypeOf((*error)(nil)).Elem()
 
+// FailureReason describes why a call to a method of a mock was marked as a
+// fail.
+type FailureReason int
+
+const (
+	// NoFailure is the reason of a call that was not marked as a fail.
+	NoFailure FailureReason = iota
+	// NoExpectationsLeft is the reason of a call to a method that has no
+	// remaining expectations.
+	NoExpectationsLeft
+	// ArgumentMismatch is the reason of a call with arguments that did not
+	// match the matchers registered with Match.
+	ArgumentMismatch
+	// TooManyCalls is the reason of a call beyond the maximum registered with
+	// ExpectAtMost or ExpectBetween.
+	TooManyCalls
+)
+
+// String returns a description of the reason.
+func (r FailureReason) String() string {
+	switch r {
+	case NoFailure:
+		return "no failure"
+	case NoExpectationsLeft:
+		return "no expectations left"
+	case ArgumentMismatch:
+		return "arguments did not match"
+	case TooManyCalls:
+		return "too many calls"
+	}
+	return fmt.Sprintf("FailureReason(%d)", int(r))
+}
+
+// UnexpectedCallError describes a call to a method of a mock that has no
+// remaining expectations.
+type UnexpectedCallError struct {
+	// Name is the name of the method.
+	Name string
+	// Args are the arguments of the call.
+	Args []any
+	// Reason is why the call was unexpected.
+	Reason FailureReason
+}
+
+// Error returns the error message.
+func (e *UnexpectedCallError) Error() string {
+	return "unexpected call to " + e.Name + ": " + e.Reason.String()
+}
+
+// UnexpectedResultCountError describes a call to a method of a mock whose
+// delegate returned a different number of results than the method.
+type UnexpectedResultCountError struct {
+	// Name is the name of the method.
+	Name string
+	// Expected is the number of results of the method.
+	Expected int
+	// Got is the number of results returned by the delegate.
+	Got int
+}
+
+// Error returns the error message.
+func (e *UnexpectedResultCountError) Error() string {
+	return fmt.Sprintf("unexpected number of results: expected %d, got %d", e.Expected, e.Got)
+}
+
+// ResultTypeError describes a call to a method of a mock whose delegate
+// returned a result of a type that is not assignable to the type of the
+// result of the method.
+type ResultTypeError struct {
+	// Name is the name of the method.
+	Name string
+	// Index is the index of the result.
+	Index int
+	// Expected is the type of the result of the method.
+	Expected reflect.Type
+	// Got is the type of the result returned by the delegate.
+	Got reflect.Type
+}
+
+// Error returns the error message.
+func (e *ResultTypeError) Error() string {
+	return fmt.Sprintf("unexpected type %s for result %d: expected %s", e.Got, e.Index, e.Expected)
+}
+
+// errorIndex returns the index of the last of the given types that is an
+// interface that any error may be assigned to, or -1 if there is none.  An
+// interface with more methods than error, such as
+// interface{ error; Temporary() bool }, is not such a type.
+func errorIndex(types []reflect.Type) int {
+	for i := len(types) - 1; i >= 0; i-- {
+		if types[i].Kind() == reflect.Interface && errType.AssignableTo(types[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
 // CallDelegate calls the next Callable of the Delegate with the given name and
 // given arguments.  If the delegate is variadic then the last argument must be
 // a slice, otherwise this function panics.  If the next Callable does not
-// exist or the last Callable is not MultiCallable, then the mock object will
-// be marked as failed.  In the case of a fail and if the delegate function
-// returns an error as its last return value, then the error will be set and
-// returned otherwise the function returns zero values for all of the return
-// values.
+// exist or the last Callable is not MultiCallable, then the default registered
+// with WithDefault is called, or if there is none the mock object will be
+// marked as failed, or if the mock was constructed with WithStrict then this
+// function panics with an *UnexpectedCallError.  A mock constructed with
+// WithFailFast is stopped with t.Fatal instead of marked as failed.  A call
+// beyond the maximum of a Callable registered with ExpectAtMost or
+// ExpectBetween is likewise marked as failed.  In the case of a fail and if
+// the delegate function returns an error as its last return value, then the
+// error will be set and returned otherwise the function returns zero values
+// for all of the return values.
 func CallDelegate[T any](key *T, name string, outTypes []reflect.Type, in ...reflect.Value) (out []reflect.Value) {
-	mock := registry[key]
+	mock := lookup(key)
+	mock.Helper()
+	return callDelegate(mock, name, outTypes, in, mock.strict, func(err error) {
+		mock.Helper()
+		if mock.failFast {
+			mock.Fatal(err)
+		} else {
+			mock.Error(err)
+		}
+	})
+}
+
+// TryCall is like CallDelegate, except that the fails of the call are
+// returned as an error, rather than marking the mock object as failed, and an
+// unexpected call is returned as an *UnexpectedCallError, rather than
+// panicking, even if the mock was constructed with WithStrict.  The results are
+// also checked against the given types, so that the error reports results of
+// the wrong number or types.  TryCall may be used to build custom Call helpers
+// with their own handling of fails.  A panic of the delegate itself is not
+// recovered.
+func TryCall[T any](key *T, name string, outTypes []reflect.Type, in ...reflect.Value) ([]reflect.Value, error) {
+	mock := lookup(key)
+	if mock == nil {
+		return nil, fmt.Errorf("mock not found: %T", key)
+	}
+	var errs []error
+	out := callDelegate(mock, name, outTypes, in, false, func(err error) {
+		errs = append(errs, err)
+	})
+	if len(out) != len(outTypes) {
+		errs = append(errs, &UnexpectedResultCountError{Name: name, Expected: len(outTypes), Got: len(out)})
+	} else {
+		for i, result := range out {
+			if result.IsValid() && !result.Type().AssignableTo(outTypes[i]) {
+				errs = append(errs, &ResultTypeError{Name: name, Index: i, Expected: outTypes[i], Got: result.Type()})
+			}
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// callDelegate calls the next Callable of the Delegate with the given name, as
+// described by CallDelegate, and reports each fail of the call to fail.  An
+// unexpected call panics if strict is true.
+func callDelegate(mock *mock, name string, outTypes []reflect.Type, in []reflect.Value, strict bool, fail func(error)) (out []reflect.Value) {
 	t := mock.TB
 	t.Helper()
 
-	delegate := delegateByName(mock, name)
+	if len(mock.allowedCallers) > 0 {
+		if caller := callerOf(); !isAllowedCaller(caller, mock.allowedCallers) {
+			fail(fmt.Errorf("call to %s from disallowed caller %s", name, caller))
+		}
+	}
+
+	if mock.contextCheck {
+		checkContexts(name, in, fail)
+	}
+
+	delegate := delegateFor(mock, name)
+	if mock.concurrency {
+		id := goroutineID()
+		for _, other := range delegate.enter(id) {
+			fail(fmt.Errorf("concurrent call to %s from goroutine %d overlaps call from goroutine %d", name, id, other))
+		}
+		defer delegate.exit(id)
+	}
 	delegate.Lock()
 	defer delegate.Unlock()
 
-	if int(delegate.callCount) >= delegate.Len() && !delegate.MultiCallable() {
-		msg := "unexpected call to " + name
-		t.Error(msg)
-		out = make([]reflect.Value, 0, len(outTypes))
-		for _, typ := range outTypes {
-			out = append(out, reflect.Zero(typ))
+	var timing string
+	if mock.timestamps {
+		now := time.Now()
+		delegate.callTimes = append(delegate.callTimes, now)
+		timing = mock.timing(now)
+	}
+
+	count := delegate.callCount
+	calls := ordered{seq: mock.seq}.call()
+	var reason FailureReason
+	defer func() {
+		mock.record(CallRecord{Name: name, Count: count, Ordinal: calls, Args: fromValues(in), Reason: reason})
+	}()
+
+	// a call with arguments that do not match neither calls nor consumes the
+	// next Callable
+	if failures := matchArgs(name, delegate.matchers, in); len(failures) > 0 {
+		reason = ArgumentMismatch
+		for _, failure := range failures {
+			fail(errors.New(failure))
+		}
+		return zeroResults(outTypes, errors.New(strings.Join(failures, "; ")))
+	}
+
+	var callErr error
+	if int(delegate.callCount) >= delegate.Len() && !delegate.MultiCallable() && delegate.fallback != nil {
+		if callErr = omittedArgErr(mock, name, delegate.fallback, in); callErr == nil {
+			mock.logf("default call to %s: %d", name, delegate.callCount)
+			defer func() { delegate.callCount++ }()
+			return delegate.fallback.Call(t, delegate.callCount, in)
 		}
-		// set last out to error
-		if i := len(out) - 1; i >= 0 && outTypes[i].Implements(errType) {
-			out[i] = reflect.ValueOf(errors.New(msg))
+	} else if int(delegate.callCount) >= delegate.Len() && !delegate.MultiCallable() {
+		reason = NoExpectationsLeft
+		unexpected := &UnexpectedCallError{Name: name, Args: fromValues(in), Reason: reason}
+		if strict {
+			panic(unexpected)
+		}
+		callErr = unexpected
+		if mock.formatter != nil {
+			callErr = &failureError{
+				msg: mock.format(Failure{Kind: UnexpectedCall, Name: name, Args: unexpected.Args, Reason: reason}),
+				err: unexpected,
+			}
 		}
-		return
+	} else if max, ok := delegate.maxCalls(); ok && int(delegate.callCount) >= max {
+		reason = TooManyCalls
+		callErr = fmt.Errorf("too many calls to %s: max %d", name, delegate.last().(bounded).max)
+		if mock.formatter != nil {
+			callErr = &failureError{
+				msg: mock.format(Failure{Kind: UnexpectedCall, Name: name, Args: fromValues(in), Reason: reason}),
+				err: callErr,
+			}
+		}
+	} else if int(delegate.callCount) < delegate.Len() {
+		callErr = omittedArgErr(mock, name, delegate.Callables[delegate.callCount], in)
+	} else {
+		callErr = omittedArgErr(mock, name, delegate.last(), in)
+	}
+	if callErr != nil {
+		fail(callErr)
+		return zeroResults(outTypes, callErr)
 	}
 
+	// only the Callables that are called once are checked for order
 	var (
-		fn Value
+		fn ordered
 		ok bool
 	)
+	next := delegate.last()
 	if int(delegate.callCount) < delegate.Len() {
-		fn, ok = delegate.Callables[delegate.callCount].(Value)
-	} else {
-		fn, ok = delegate.Callables[delegate.Len()-1].(Value)
+		next = delegate.Callables[delegate.callCount]
+	}
+	switch next := next.(type) {
+	case Value:
+		fn, ok = next.ordered, true
+	case panicking:
+		fn, ok = next.ordered, true
 	}
 
-	if fn.inOrder {
-		mock.ordinal++
+	if ok {
+		if fn.seq == nil {
+			// a Value appended directly to the Delegate is ordered by
+			// the mock's own Sequence
+			fn.seq = mock.seq
+		}
+		calls = fn.call()
 	}
 
-	if ok && fn.ordinal != mock.ordinal {
-		err := fmt.Sprintf("out of order call to %s: expected %d, got %d", name, fn.ordinal, mock.ordinal)
-		t.Error(err)
+	if ok && fn.ordinal != calls {
+		fail(errors.New(mock.format(Failure{
+			Kind:         OutOfOrderCall,
+			Name:         name,
+			Expected:     int(fn.ordinal),
+			Got:          int(calls),
+			ExpectedName: fn.seq.nameAt(calls),
+		})))
 	}
 
-	t.Logf("call to %s: %d/%d", name, delegate.callCount, mock.ordinal)
+	mock.logf("call to %s: %d/%d%s", name, delegate.callCount, calls, timing)
 	defer func() { delegate.callCount++ }()
 	return delegate.Call(t, delegate.callCount, in)
 }
 
-// toValues converts the given values to reflect.Values.
+// zeroResults returns the zero values of the given types, except that the
+// error result, wherever it is, is set to an error with the message of err.
+func zeroResults(outTypes []reflect.Type, err error) []reflect.Value {
+	out := make([]reflect.Value, 0, len(outTypes))
+	for _, typ := range outTypes {
+		out = append(out, reflect.Zero(typ))
+	}
+	if i := errorIndex(outTypes); i >= 0 {
+		out[i] = reflect.ValueOf(errors.New(err.Error()))
+	}
+	return out
+}
+
+// toValues converts the given values to reflect.Values.  A nil value is
+// converted to an invalid reflect.Value, which Value.Call replaces by the
+// zero value of the parameter of the delegate.
 func toValues(in ...any) (out []reflect.Value) {
 	out = make([]reflect.Value, len(in))
 	for i, v := range in {
@@ -167,14 +568,25 @@ func toValues(in ...any) (out []reflect.Value) {
 	return
 }
 
+// fromValues converts the given reflect.Values to values.
+func fromValues(in []reflect.Value) (out []any) {
+	out = make([]any, len(in))
+	for i, v := range in {
+		out[i] = valueOf(v)
+	}
+	return
+}
+
 // doCall calls the next Callable of the Delegate with the given name and given
 // arguments and sets the given out values to the return values of the Callable.
 // If the types of the return values do not match the types of the out values,
 // or if the number of return values does not match the number of out values,
-// then the last out value will be set to an error if it is assignable to an
-// error type otherwise this function will panic.
+// then the error out value will be set to a *ResultTypeError or an
+// *UnexpectedResultCountError respectively, or if there is no error out value
+// then the last out value will be set to the error if it is assignable to an
+// error type, otherwise this function will panic with the error.
 func doCall[T any](key *T, name string, in []reflect.Value, out []reflect.Value) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	outTypes := make([]reflect.Type, len(out))
 	for i := range out {
 		outTypes[i] = out[i].Type().Elem()
@@ -183,7 +595,7 @@ func doCall[T any](key *T, name string, in []reflect.Value, out []reflect.Value)
 	last := len(outTypes) - 1
 	var err error
 	if len(results) != len(outTypes) {
-		err = fmt.Errorf("unexpected number of results: expected %d, got %d", len(outTypes), len(results))
+		err = &UnexpectedResultCountError{Name: name, Expected: len(outTypes), Got: len(results)}
 	}
 	for i := range out {
 		if err != nil {
@@ -193,12 +605,15 @@ func doCall[T any](key *T, name string, in []reflect.Value, out []reflect.Value)
 			if results[i].Type().AssignableTo(outTypes[i]) {
 				out[i].Elem().Set(results[i])
 			} else {
-				err = fmt.Errorf("unexpected type %T for result parameter %T", results[i].Interface(), out[i].Interface())
+				err = &ResultTypeError{Name: name, Index: i, Expected: outTypes[i], Got: results[i].Type()}
 			}
 		}
 	}
 	if err != nil {
-		registry[key].Error(err)
+		lookup(key).Error(err)
+		if i := errorIndex(outTypes); i >= 0 {
+			last = i
+		}
 		t2 := outTypes[last]
 		if reflect.TypeOf(err).ConvertibleTo(t2) {
 			out[last].Elem().Set(reflect.ValueOf(err).Convert(t2))
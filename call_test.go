@@ -45,6 +45,19 @@ func TestDoCall(t *testing.T) {
 			results:    toValues("result"),
 			expectFail: false,
 		},
+		{
+			name: "Matching types and values, call count",
+			callables: Callables{Value{Value: reflect.ValueOf(func(count CallCount, in string) string {
+				if count != 0 {
+					t.Errorf("unexpected count: expected %d, got %d", 0, count)
+				}
+				return "result"
+			})}},
+			in:         toValues("input"),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
 		{
 			name: "Matching types and values, variadic",
 			callables: Callables{Value{Value: reflect.ValueOf(func(t testing.TB, in ...string) string {
@@ -58,6 +71,120 @@ func TestDoCall(t *testing.T) {
 			results:    toValues("result"),
 			expectFail: false,
 		},
+		{
+			name: "Variadic empty",
+			callables: Callables{Value{Value: reflect.ValueOf(func(in ...string) string {
+				if len(in) != 0 {
+					t.Errorf("unexpected input: expected none, got %q", in)
+				}
+				return "result"
+			})}},
+			in:         toValues([]string(nil)),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
+		{
+			name: "Variadic missing",
+			callables: Callables{Value{Value: reflect.ValueOf(func(in ...string) string {
+				if len(in) != 0 {
+					t.Errorf("unexpected input: expected none, got %q", in)
+				}
+				return "result"
+			})}},
+			in:         toValues(),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
+		{
+			name: "Variadic missing, testing.TB",
+			callables: Callables{Value{Value: reflect.ValueOf(func(t testing.TB, in ...string) string {
+				if len(in) != 0 {
+					t.Errorf("unexpected input: expected none, got %q", in)
+				}
+				return "result"
+			})}},
+			in:         toValues(),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
+		{
+			name: "Variadic missing, multi",
+			callables: Callables{multi{Value: reflect.ValueOf(func(t testing.TB, count CallCount, in ...string) string {
+				if len(in) != 0 {
+					t.Errorf("unexpected input: expected none, got %q", in)
+				}
+				return "result"
+			})}},
+			in:         toValues(),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
+		{
+			name: "Nil interface argument",
+			callables: Callables{Value{Value: reflect.ValueOf(func(t testing.TB, key string, value any) error {
+				if value != nil {
+					t.Errorf("unexpected input: expected nil, got %v", value)
+				}
+				return nil
+			})}},
+			in:         toValues("key", nil),
+			out:        toValues(new(error)),
+			results:    []reflect.Value{reflect.Zero(errType)},
+			expectFail: false,
+		},
+		{
+			name: "Nil pointer argument, CallCount",
+			callables: Callables{Value{Value: reflect.ValueOf(func(count CallCount, in *string) string {
+				if in != nil {
+					t.Errorf("unexpected input: expected nil, got %v", in)
+				}
+				return "result"
+			})}},
+			in:         toValues(nil),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
+		{
+			name: "Variadic omitted",
+			callables: Callables{Value{Value: reflect.ValueOf(func() string {
+				return "result"
+			})}},
+			in:         toValues([]string{"input"}),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
+		{
+			name: "Variadic omitted, testing.TB",
+			callables: Callables{Value{Value: reflect.ValueOf(func(t testing.TB, in string) string {
+				if in != "input" {
+					t.Errorf("unexpected input: expected %q, got %q", "input", in)
+				}
+				return "result"
+			})}},
+			in:         toValues("input", []string{"ignored"}),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
+		{
+			name: "Variadic omitted, multi",
+			callables: Callables{multi{Value: reflect.ValueOf(func(t testing.TB, count CallCount) string {
+				if count != 0 {
+					t.Errorf("unexpected count: expected %d, got %d", 0, count)
+				}
+				return "result"
+			})}},
+			in:         toValues([]string{"ignored"}),
+			out:        toValues(new(string)),
+			results:    toValues("result"),
+			expectFail: false,
+		},
 		{
 			name: "Type mismatch",
 			callables: Callables{Value{Value: reflect.ValueOf(func() string {
@@ -69,6 +196,16 @@ func TestDoCall(t *testing.T) {
 			expectFail:  true,
 			expectPanic: true,
 		},
+		{
+			name: "Type mismatch, error",
+			callables: Callables{Value{Value: reflect.ValueOf(func() (string, error) {
+				return "result", nil
+			})}},
+			in:         toValues(),
+			out:        toValues(new(int), new(error)),
+			results:    toValues(0, &ResultTypeError{Name: "testMethod", Index: 0, Expected: reflect.TypeOf(0), Got: reflect.TypeOf("")}),
+			expectFail: true,
+		},
 		{
 			name:        "Unexpected number of results, panic",
 			callables:   Callables{Value{Value: reflect.ValueOf(func() {})}},
@@ -83,7 +220,7 @@ func TestDoCall(t *testing.T) {
 			callables:  Callables{Value{Value: reflect.ValueOf(func() {})}},
 			in:         toValues(),
 			out:        toValues(new(error)),
-			results:    toValues(errors.New("unexpected number of results: expected 1, got 0")),
+			results:    toValues(&UnexpectedResultCountError{Name: "testMethod", Expected: 1, Got: 0}),
 			expectFail: true,
 		},
 		{
@@ -91,7 +228,23 @@ func TestDoCall(t *testing.T) {
 			callables:  Callables{},
 			in:         toValues(),
 			out:        toValues(new(error)),
-			results:    toValues(errors.New("unexpected call to testMethod")),
+			results:    toValues(errors.New("unexpected call to testMethod: no expectations left")),
+			expectFail: true,
+		},
+		{
+			name:       "Unexpected number of calls, error first",
+			callables:  Callables{},
+			in:         toValues(),
+			out:        toValues(new(error), new(bool)),
+			results:    toValues(errors.New("unexpected call to testMethod: no expectations left"), false),
+			expectFail: true,
+		},
+		{
+			name:       "Unexpected number of results, error first",
+			callables:  Callables{Value{Value: reflect.ValueOf(func() {})}},
+			in:         toValues(),
+			out:        toValues(new(error), new(bool)),
+			results:    toValues(&UnexpectedResultCountError{Name: "testMethod", Expected: 2, Got: 0}, false),
 			expectFail: true,
 		},
 	}
@@ -130,6 +283,7 @@ func TestDoCall(t *testing.T) {
 						Callables: tt.callables,
 					},
 				},
+				ordered: ordered{seq: NewSequence()},
 			}
 			t.Cleanup(func() {
 				delete(registry, key)
@@ -140,3 +294,93 @@ func TestDoCall(t *testing.T) {
 		})
 	}
 }
+
+func TestDoCall_panicError(t *testing.T) {
+	key := new(struct{ _ byte })
+	mockT := new(testing.T)
+	registry[key] = &mock{
+		TB: mockT,
+		Delegates: Delegates{
+			"testMethod": &Delegate{
+				Callables: Callables{Value{Value: reflect.ValueOf(func() {})}},
+			},
+		},
+		ordered: ordered{seq: NewSequence()},
+	}
+	t.Cleanup(func() {
+		delete(registry, key)
+	})
+
+	defer func() {
+		err, _ := recover().(error)
+		var countErr *UnexpectedResultCountError
+		if !errors.As(err, &countErr) {
+			t.Fatalf("expected *UnexpectedResultCountError, got %v", err)
+		}
+		if countErr.Name != "testMethod" || countErr.Expected != 1 || countErr.Got != 0 {
+			t.Errorf("unexpected error: %+v", countErr)
+		}
+	}()
+	doCall(key, "testMethod", toValues(), toValues(new(int)))
+}
+
+type omitter struct{ _ byte }
+
+func (*omitter) Put(key string, data []byte) {}
+func (*omitter) Load(keys ...string)         {}
+
+func TestCallDelegate_omittedArg(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		delegate   any
+		expectFail bool
+	}{
+		{name: "Variadic", method: "Load", delegate: func() {}},
+		{name: "Not variadic", method: "Put", delegate: func(key string) {}, expectFail: true},
+		{name: "Not variadic, CallCount", method: "Put", delegate: func(count CallCount, key string) {}, expectFail: true},
+		{name: "Not omitted", method: "Put", delegate: func(key string, data []byte) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := new(testing.T)
+			key := New(mockT, Expect[omitter](tt.method, tt.delegate))
+			in := toValues([]string{"key"})
+			if tt.method == "Put" {
+				in = toValues("key", []byte("data"))
+			}
+			CallDelegate(key, tt.method, nil, in...)
+			if got := mockT.Failed(); got != tt.expectFail {
+				t.Errorf("expected failed to be %v, got %v", tt.expectFail, got)
+			}
+		})
+	}
+}
+
+// tempErr is an interface that not every error implements.
+type tempErr interface {
+	error
+	Temporary() bool
+}
+
+func TestDoCall_wideErrorResult(t *testing.T) {
+	key := new(struct{ _ byte })
+	mockT := new(testing.T)
+	registry[key] = &mock{
+		TB:        mockT,
+		Delegates: Delegates{"testMethod": &Delegate{}},
+		ordered:   ordered{seq: NewSequence()},
+	}
+	t.Cleanup(func() {
+		delete(registry, key)
+	})
+
+	out := toValues(new(int), new(tempErr))
+	doCall(key, "testMethod", toValues(), out)
+	if !mockT.Failed() {
+		t.Error("expected failure for unexpected call")
+	}
+	if err := out[1].Elem().Interface(); err != nil {
+		t.Errorf("expected nil result, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package vermock
+
+import (
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// pkgPrefix is the prefix of the fully qualified names of functions in this
+// package.
+var pkgPrefix = reflect.TypeOf(mock{}).PkgPath() + "."
+
+// WithAllowedCallers restricts the callers of the mock's methods to functions
+// whose fully qualified names match one of the given patterns, such as
+// "example.com/app/service.*" or "example.com/app/service.(*Service).Run".
+// Patterns use the syntax of path.Match.  A call from any other function will
+// be marked as a fail.
+//
+// The caller is found by inspecting the call stack on every call to the mock,
+// which is relatively expensive, so this check is only made when this option
+// is given.
+func WithAllowedCallers[T any](patterns ...string) Option[T] {
+	return func(key *T) {
+		mock := lookup(key)
+		mock.allowedCallers = append(mock.allowedCallers, patterns...)
+	}
+}
+
+// callerOf returns the fully qualified name of the function that called the
+// mock method that called into this package.
+func callerOf() string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	// skip the frames of this package, then the frame of the mock method
+	skipped := false
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPrefix) {
+			if skipped {
+				return frame.Function
+			}
+			skipped = true
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isAllowedCaller reports whether the caller matches any of the patterns.
+func isAllowedCaller(caller string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, caller); ok {
+			return true
+		}
+	}
+	return false
+}
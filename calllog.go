@@ -0,0 +1,66 @@
+package vermock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CallRecord describes a call to a method of a mock, as returned by CallLog.
+type CallRecord struct {
+	// Name is the name of the method.
+	Name string
+	// Count is the number of calls made to the method before this call.
+	Count CallCount
+	// Ordinal is the number of ordered calls made in the Sequence of this
+	// call, up to and including this call.
+	Ordinal uint
+	// Args are the arguments of the call.
+	Args []any
+	// Reason is why the call was marked as a fail, or NoFailure.  When
+	// there is more than one reason, the last is recorded.
+	Reason FailureReason
+}
+
+// String formats the call as the method name followed by its arguments.
+func (r CallRecord) String() string {
+	args := make([]string, len(r.Args))
+	for i, arg := range r.Args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+	return fmt.Sprintf("%s(%s)", r.Name, strings.Join(args, ", "))
+}
+
+// CallLog returns a record of each call made to the methods of the given mock,
+// in the order that the calls were made, including calls that were marked as
+// a fail.  It returns nil if the mock is not found.  This is useful to print
+// a timeline of calls when debugging a test.
+func CallLog[T any](key *T) []CallRecord {
+	mock := lookup(key)
+	if mock == nil {
+		return nil
+	}
+	mock.logMu.Lock()
+	defer mock.logMu.Unlock()
+	return append([]CallRecord(nil), mock.log...)
+}
+
+// Args returns the arguments of each call made to the named method of the
+// given mock, in the order that the calls were made, including calls that
+// were marked as a fail.  It returns nil if the mock is not found or the
+// method was not called.  This is useful to assert on the full history of
+// calls after the code under test has run.
+func Args[T any](key *T, name string) (args [][]any) {
+	for _, record := range CallLog(key) {
+		if record.Name == name {
+			args = append(args, record.Args)
+		}
+	}
+	return
+}
+
+// record appends a record of a call to the log of the mock.
+func (m *mock) record(r CallRecord) {
+	m.logMu.Lock()
+	defer m.logMu.Unlock()
+	m.log = append(m.log, r)
+}
@@ -0,0 +1,109 @@
+package vermock_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestCallLog(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.ExpectInOrder(
+			vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+			vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return "bar", true }),
+		),
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return "bar", true }),
+	)
+	_ = cache.Put("foo", "bar")
+	cache.Get("foo")
+	cache.Get("foo")
+	cache.Delete("foo")
+	if !mockT.Failed() {
+		t.Error("expected unexpected call to Delete to fail")
+	}
+
+	want := []vermock.CallRecord{
+		{Name: "Put", Count: 0, Ordinal: 1, Args: []any{"foo", "bar"}},
+		{Name: "Get", Count: 0, Ordinal: 2, Args: []any{"foo"}},
+		{Name: "Get", Count: 1, Ordinal: 2, Args: []any{"foo"}},
+		{Name: "Delete", Count: 0, Ordinal: 2, Args: []any{"foo"}, Reason: vermock.NoExpectationsLeft},
+	}
+	log := vermock.CallLog(cache)
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected log %v, got %v", want, log)
+	}
+	if s := log[0].String(); s != `Put("foo", "bar")` {
+		t.Errorf("unexpected string: %s", s)
+	}
+}
+
+func TestCallLog_reason(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.Match[mockCache]("Get", vermock.Eq("foo")),
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return nil, false }),
+		vermock.ExpectAtMost[mockCache]("Delete", 1, func(key string) {}),
+		vermock.WithStrict[mockCache](),
+	)
+	cache.Get("bar")
+	cache.Get("foo")
+	func() {
+		defer func() {
+			var callErr *vermock.UnexpectedCallError
+			if err, ok := recover().(error); !ok || !errors.As(err, &callErr) {
+				t.Fatalf("expected *vermock.UnexpectedCallError, got %v", err)
+			}
+			if callErr.Reason != vermock.NoExpectationsLeft {
+				t.Errorf("unexpected reason: %v", callErr.Reason)
+			}
+			if msg := callErr.Error(); msg != "unexpected call to Get: no expectations left" {
+				t.Errorf("unexpected message: %s", msg)
+			}
+		}()
+		cache.Get("foo")
+	}()
+	cache.Delete("foo")
+	cache.Delete("foo")
+
+	var reasons []vermock.FailureReason
+	for _, record := range vermock.CallLog(cache) {
+		reasons = append(reasons, record.Reason)
+	}
+	want := []vermock.FailureReason{vermock.ArgumentMismatch, vermock.NoFailure, vermock.NoExpectationsLeft, vermock.NoFailure, vermock.TooManyCalls}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("expected reasons %v, got %v", want, reasons)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.ExpectMany[mockCache]("Put", func(key string, value any) error {
+			return nil
+		}),
+		vermock.ExpectMany[mockCache]("Delete", func(key string) {}),
+	)
+	if args := vermock.Args(cache, "Put"); args != nil {
+		t.Errorf("expected no args before calls, got %v", args)
+	}
+	for i, key := range []string{"a", "b", "c"} {
+		_ = cache.Put(key, i)
+		cache.Delete(key)
+	}
+
+	var keys []string
+	for _, args := range vermock.Args(cache, "Put") {
+		keys = append(keys, args[0].(string))
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected keys %q, got %q", want, keys)
+	}
+	if want := [][]any{{"a", 0}, {"b", 1}, {"c", 2}}; !reflect.DeepEqual(vermock.Args(cache, "Put"), want) {
+		t.Errorf("expected args %v, got %v", want, vermock.Args(cache, "Put"))
+	}
+	if args := vermock.Args(cache, "Get"); args != nil {
+		t.Errorf("expected no args for uncalled Get, got %v", args)
+	}
+}
@@ -0,0 +1,51 @@
+package vermock
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ArgCaptor records the values of an argument of the calls to a method of a
+// mock, so that they may be asserted on after the code under test has run.
+// An ArgCaptor is an ArgMatcher that matches any argument of type A, and may
+// be given to Match in the position of the argument to capture.
+type ArgCaptor[A any] struct {
+	mu     sync.Mutex
+	values []A
+}
+
+// Captor returns a new ArgCaptor for arguments of type A.
+func Captor[A any]() *ArgCaptor[A] {
+	return new(ArgCaptor[A])
+}
+
+// Match records the argument and reports whether it is of type A.  An
+// invalid argument, such as that of a nil interface, is recorded as the zero
+// value of A.
+func (c *ArgCaptor[A]) Match(arg reflect.Value) bool {
+	var value A
+	if arg.IsValid() {
+		if !arg.Type().AssignableTo(reflect.TypeOf(&value).Elem()) {
+			return false
+		}
+		reflect.ValueOf(&value).Elem().Set(arg)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = append(c.values, value)
+	return true
+}
+
+// String describes the captor for failure messages.
+func (c *ArgCaptor[A]) String() string {
+	var value A
+	return fmt.Sprintf("Captor[%s]()", reflect.TypeOf(&value).Elem())
+}
+
+// Captured returns the recorded arguments, in the order of the calls.
+func (c *ArgCaptor[A]) Captured() []A {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]A(nil), c.values...)
+}
@@ -0,0 +1,47 @@
+package vermock_test
+
+import (
+	"reflect"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestCaptor(t *testing.T) {
+	keys := vermock.Captor[string]()
+	values := vermock.Captor[any]()
+	cache := vermock.New(t,
+		vermock.Match[mockCache]("Put", keys, values),
+		vermock.ExpectMany[mockCache]("Put", func(key string, value any) error {
+			return nil
+		}),
+	)
+	_ = cache.Put("foo", 1)
+	_ = cache.Put("bar", nil)
+
+	if got, want := keys.Captured(), []string{"foo", "bar"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected keys: expected %v, got %v", want, got)
+	}
+	if got, want := values.Captured(), []any{1, nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected values: expected %v, got %v", want, got)
+	}
+}
+
+func TestCaptor_typeMismatch(t *testing.T) {
+	mockT := &testing.T{}
+	keys := vermock.Captor[int]()
+	cache := vermock.New(mockT,
+		vermock.Match[mockCache]("Delete", keys),
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+	)
+	cache.Delete("foo")
+	if !mockT.Failed() {
+		t.Error("expected failure")
+	}
+	if got := keys.Captured(); len(got) != 0 {
+		t.Errorf("expected no captured keys, got %v", got)
+	}
+	if s := keys.String(); s != "Captor[int]()" {
+		t.Errorf("unexpected string: %q", s)
+	}
+}
@@ -17,6 +17,7 @@ func main() {
 	subcommands.Register(subcommands.FlagsCommand(), "")
 	subcommands.Register(subcommands.HelpCommand(), "")
 	subcommands.Register(&vermockgen.GenCmd{}, "")
+	subcommands.Register(&vermockgen.ListCmd{}, "")
 
 	// Initialize the default logger to log to stderr.
 	log.SetFlags(0)
@@ -0,0 +1,54 @@
+package vermock
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// DetectConcurrency declares the methods of the mock non-concurrent.  Each
+// call records the calling goroutine, and a call to a method that overlaps a
+// call to the same method from another goroutine is marked as a fail.  Calls
+// are still serialized, so the overlapping call is made once the other
+// returns.
+func DetectConcurrency[T any]() Option[T] {
+	return func(key *T) {
+		lookup(key).concurrency = true
+	}
+}
+
+// enter records a call to the delegate from the goroutine with the given id,
+// and returns the goroutines of the calls in progress.
+func (d *Delegate) enter(id uint64) (others []uint64) {
+	d.activeMu.Lock()
+	defer d.activeMu.Unlock()
+	others = append(others, d.active...)
+	d.active = append(d.active, id)
+	return
+}
+
+// exit removes a call to the delegate from the goroutine with the given id.
+func (d *Delegate) exit(id uint64) {
+	d.activeMu.Lock()
+	defer d.activeMu.Unlock()
+	for i, active := range d.active {
+		if active == id {
+			d.active = append(d.active[:i], d.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// goroutineID returns the id of the calling goroutine, as reported in the
+// first line of its stack trace, or 0 if it cannot be parsed.
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	// the first line is like "goroutine 1 [running]:"
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
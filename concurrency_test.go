@@ -0,0 +1,64 @@
+package vermock_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestDetectConcurrency(t *testing.T) {
+	t.Run("sequential", func(t *testing.T) {
+		mockT := &testing.T{}
+		cache := vermock.New(mockT,
+			vermock.DetectConcurrency[mockCache](),
+			vermock.ExpectMany[mockCache]("Delete", func(key string) {}),
+		)
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				cache.Delete("foo")
+			}()
+			wg.Wait()
+		}
+		if mockT.Failed() {
+			t.Error("expected no failure")
+		}
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+		mockT := &testing.T{}
+		started := make(chan struct{})
+		release := make(chan struct{})
+		cache := vermock.New(mockT,
+			vermock.DetectConcurrency[mockCache](),
+			vermock.Expect[mockCache]("Delete", func(key string) {
+				close(started)
+				<-release
+			}),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cache.Delete("foo")
+		}()
+		<-started
+		go func() {
+			defer wg.Done()
+			cache.Delete("bar")
+		}()
+		for deadline := time.Now().Add(time.Second); !mockT.Failed() && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+		wg.Wait()
+		if !mockT.Failed() {
+			t.Error("expected failure for overlapping calls")
+		}
+	})
+}
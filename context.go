@@ -0,0 +1,42 @@
+package vermock
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// contextType is the type of the context.Context interface.
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// WithContextCheck makes a call to a method of the mock with a context.Context
+// argument that is already done, whether cancelled or past its deadline,
+// be marked as a fail.
+func WithContextCheck[T any]() Option[T] {
+	return func(key *T) {
+		lookup(key).contextCheck = true
+	}
+}
+
+// checkContexts reports a fail of a call to the method with the given name for
+// each of the given arguments that is a done context.Context.
+func checkContexts(name string, in []reflect.Value, fail func(error)) {
+	for _, arg := range in {
+		if !arg.IsValid() || !arg.Type().Implements(contextType) {
+			continue
+		}
+		if ctx, ok := arg.Interface().(context.Context); ok && ctx != nil && ctx.Err() != nil {
+			fail(fmt.Errorf("call to %s made with cancelled context: %w", name, ctx.Err()))
+		}
+	}
+}
+
+// AssertContextActive asserts that the given context is not done.  It may be
+// used by a delegate to check the context that it is called with.
+func AssertContextActive(t testing.TB, ctx context.Context) {
+	t.Helper()
+	if err := ctx.Err(); err != nil {
+		t.Errorf("context is done: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package vermock_test
+
+import (
+	"context"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+type mockFetcher struct {
+	_ byte // prevent zero-sized type
+}
+
+func (m *mockFetcher) Fetch(ctx context.Context, url string) error {
+	return vermock.Call1[error](m, "Fetch", ctx, url)
+}
+
+func TestWithContextCheck(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, tc := range []struct {
+		name   string
+		ctx    context.Context
+		failed bool
+	}{
+		{name: "active", ctx: context.Background()},
+		{name: "cancelled", ctx: cancelled, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			fetcher := vermock.New(mockT,
+				vermock.WithContextCheck[mockFetcher](),
+				vermock.Expect[mockFetcher]("Fetch", func(ctx context.Context, url string) error {
+					return nil
+				}),
+			)
+			_ = fetcher.Fetch(tc.ctx, "https://example.com")
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
+
+func TestAssertContextActive(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	mockT := &testing.T{}
+	fetcher := vermock.New(mockT,
+		vermock.ExpectMany[mockFetcher]("Fetch", func(t testing.TB, ctx context.Context, url string) error {
+			vermock.AssertContextActive(t, ctx)
+			return nil
+		}),
+	)
+	_ = fetcher.Fetch(context.Background(), "https://example.com")
+	if mockT.Failed() {
+		t.Error("expected no failure with an active context")
+	}
+	_ = fetcher.Fetch(cancelled, "https://example.com")
+	if !mockT.Failed() {
+		t.Error("expected failure with a cancelled context")
+	}
+}
@@ -0,0 +1,25 @@
+package vermock
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithDefault registers a function to be called for each call of the method
+// with the given name that has no remaining expectations, instead of marking
+// the call as a fail.  The default does not need to be called, so it does not
+// affect AssertExpectedCalls.  Like ExpectMany, the arguments of fn must match
+// the named method signature and may optionally be preceded by a testing.TB
+// or *testing.T and a CallCount.
+// Panics if fn is not a function.
+func WithDefault[T any](name string, fn any) Option[T] {
+	if funcType := reflect.TypeOf(fn); funcType == nil || funcType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("vermock.WithDefault: expected function, got %T", fn))
+	}
+	return func(key *T) {
+		delegate := delegateByName(lookup(key), name)
+		delegate.Lock()
+		defer delegate.Unlock()
+		delegate.fallback = Value{Value: reflect.ValueOf(fn)}
+	}
+}
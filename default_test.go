@@ -0,0 +1,48 @@
+package vermock_test
+
+import (
+	"reflect"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestWithDefault(t *testing.T) {
+	mockT := &testing.T{}
+	var counts []vermock.CallCount
+	cache := vermock.New(mockT,
+		vermock.WithStrict[mockCache](),
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) {
+			return "bar", true
+		}),
+		vermock.WithDefault[mockCache]("Get", func(n vermock.CallCount, key string) (any, bool) {
+			counts = append(counts, n)
+			return nil, false
+		}),
+		vermock.WithDefault[mockCache]("Delete", func(key string) {}),
+	)
+	if value, ok := cache.Get("foo"); value != "bar" || !ok {
+		t.Errorf("unexpected result: %v, %v", value, ok)
+	}
+	for i := 0; i < 2; i++ {
+		if value, ok := cache.Get("foo"); value != nil || ok {
+			t.Errorf("unexpected default result: %v, %v", value, ok)
+		}
+	}
+	if want := []vermock.CallCount{1, 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected default call counts %v, got %v", want, counts)
+	}
+	vermock.AssertExpectedCalls(mockT, cache)
+	if mockT.Failed() {
+		t.Error("expected no failure")
+	}
+}
+
+func TestWithDefault_invalid(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "vermock.WithDefault: expected function, got string" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	vermock.WithDefault[mockCache]("Get", "bar")
+}
@@ -1,6 +1,9 @@
 package vermock
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 type CallCount int
 
@@ -9,6 +12,18 @@ type Delegate struct {
 	sync.Mutex
 	Callables
 	callCount CallCount
+	callTimes []time.Time
+	matchers  []ArgMatcher
+	// descriptions maps the index of a Callable to its description, as
+	// registered with Describe.
+	descriptions map[int]string
+	// fallback, if not nil, is called when there are no expectations left,
+	// as registered with WithDefault.
+	fallback Callable
+	// active holds the goroutines in calls to the delegate, as recorded when
+	// the mock was created with DetectConcurrency.
+	activeMu sync.Mutex
+	active   []uint64
 }
 
 // Append adds one or more callables to the delegate.
@@ -19,6 +34,13 @@ func (d *Delegate) Append(callable ...Callable) Callables {
 	return d.Callables
 }
 
+// empty reports whether the delegate has no Callables, default or matchers.
+func (d *Delegate) empty() bool {
+	d.Lock()
+	defer d.Unlock()
+	return d.Len() == 0 && d.fallback == nil && len(d.matchers) == 0
+}
+
 // delegateByName retrieves or creates a Delegate for a given method name.  It
 // is safe to call from multiple goroutines.
 func delegateByName(mock *mock, name string) (delegate *Delegate) {
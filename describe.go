@@ -0,0 +1,76 @@
+package vermock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Describe applies the given options and attaches the description to each
+// call that they register, for example the expected arguments of the call.
+// When a call is not made, AssertExpectedCalls includes its description in
+// the failure, e.g. "expected call 2/3 to Get(\"baz\")".  Without a
+// description, a call of a method with matchers registered by Match is
+// described by its matchers.
+func Describe[T any](description string, options ...Option[T]) Option[T] {
+	return func(key *T) {
+		for _, r := range register(key, Options(options...)) {
+			r.delegate.Lock()
+			for i := r.from; i < r.delegate.Len(); i++ {
+				if r.delegate.descriptions == nil {
+					r.delegate.descriptions = make(map[int]string)
+				}
+				r.delegate.descriptions[i] = description
+			}
+			r.delegate.Unlock()
+		}
+	}
+}
+
+// registration is a Delegate to which an Option registered calls, from the
+// index of the first of them.
+type registration struct {
+	delegate *Delegate
+	from     int
+}
+
+// register applies the option and returns the Delegates of the mock,
+// including those of its prefixes, to which it registered calls, keyed as by
+// allDelegates.
+func register[T any](key *T, option Option[T]) map[string]registration {
+	mock := lookup(key)
+	before := make(map[string]int)
+	for name, delegate := range allDelegates(mock) {
+		delegate.Lock()
+		before[name] = delegate.Len()
+		delegate.Unlock()
+	}
+	option(key)
+	registrations := make(map[string]registration)
+	for name, delegate := range allDelegates(mock) {
+		delegate.Lock()
+		if delegate.Len() > before[name] {
+			registrations[name] = registration{delegate: delegate, from: before[name]}
+		}
+		delegate.Unlock()
+	}
+	return registrations
+}
+
+// describe returns the description of the call at the given index of the
+// delegate of the method with the given name, or false if there is none.
+func (d *Delegate) describe(name string, index int) (string, bool) {
+	if index >= d.Len() {
+		index = d.Len() - 1
+	}
+	if description, ok := d.descriptions[index]; ok {
+		return description, true
+	}
+	if len(d.matchers) == 0 {
+		return "", false
+	}
+	matchers := make([]string, len(d.matchers))
+	for i, matcher := range d.matchers {
+		matchers[i] = matcher.String()
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(matchers, ", ")), true
+}
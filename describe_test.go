@@ -0,0 +1,60 @@
+package vermock_test
+
+import (
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestDescribe(t *testing.T) {
+	get := func(key string) (any, bool) { return nil, false }
+	for _, tc := range []struct {
+		name string
+		opts []vermock.Option[mockCache]
+		want string
+	}{
+		{
+			name: "undescribed",
+			opts: []vermock.Option[mockCache]{
+				vermock.Expect[mockCache]("Get", get),
+				vermock.Expect[mockCache]("Get", get),
+			},
+			want: "failed to make call to Get: only got one call",
+		},
+		{
+			name: "described",
+			opts: []vermock.Option[mockCache]{
+				vermock.Describe(`Get("foo")`, vermock.Expect[mockCache]("Get", get)),
+				vermock.Describe(`Get("baz")`, vermock.ExpectTimes[mockCache]("Get", 2, get)),
+			},
+			want: `failed to make call to Get: only got one call: expected call 2/3 to Get("baz")`,
+		},
+		{
+			name: "matchers",
+			opts: []vermock.Option[mockCache]{
+				vermock.Match[mockCache]("Get", vermock.AnyOf("foo", "baz")),
+				vermock.ExpectTimes[mockCache]("Get", 2, get),
+			},
+			want: `failed to make call to Get: only got one call: expected call 2/2 to Get(AnyOf("foo", "baz"))`,
+		},
+		{
+			name: "prefix",
+			opts: []vermock.Option[mockCache]{
+				vermock.Expect[mockCache]("Get", get),
+				vermock.Describe(`Delete("foo")`, vermock.ExpectPrefix[mockCache]("De", func(key string) {})),
+			},
+			want: `failed to make call to De*: expected one call, got none: expected call 1/1 to Delete("foo")`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := vermock.New(t, tc.opts...)
+			cache.Get("foo")
+			defer func() {
+				if r := recover(); r != tc.want {
+					t.Errorf("unexpected failure: %v", r)
+				}
+			}()
+			vermock.AssertExpectedCallsWith(t, []vermock.AssertOption{vermock.WithPanicOnFail()}, cache)
+		})
+	}
+}
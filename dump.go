@@ -0,0 +1,130 @@
+package vermock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+)
+
+// DumpExpectations writes a textual representation of the expectations
+// configured for the given mock to w.  Each line describes one expected call
+// as the method name, the index of the call, the cardinality of the call
+// (once, many, between min and max, spy or panic), the signature of the
+// delegate and, for ordered calls, its ordinal.  Methods are sorted by name
+// and calls are listed in the order they were registered, so the output is
+// stable and suitable for golden files.
+func DumpExpectations[T any](key *T, w io.Writer) error {
+	mock := lookup(key)
+	if mock == nil {
+		return fmt.Errorf("mock not found: %T", key)
+	}
+
+	names, delegates := sortedDelegates(mock)
+	for i, name := range names {
+		delegate := delegates[i]
+		delegate.Lock()
+		callables := delegate.Callables
+		delegate.Unlock()
+		for j, callable := range callables {
+			var line string
+			switch callable := callable.(type) {
+			case Value:
+				line = fmt.Sprintf("%s %d once %s", name, j, callable.Type()) + dumpOrdered(callable.ordered)
+			case multi:
+				line = fmt.Sprintf("%s %d many %s", name, j, callable.Type()) + dumpOrdered(callable.ordered)
+			case bounded:
+				line = fmt.Sprintf("%s %d between %d %d %s", name, j, callable.min, callable.max, callable.Type()) + dumpOrdered(callable.ordered)
+			case *spy:
+				line = fmt.Sprintf("%s %d spy %s", name, j, callable.Type()) + dumpOrdered(callable.ordered)
+			case panicking:
+				line = fmt.Sprintf("%s %d panic %v", name, j, callable.value)
+			default:
+				line = fmt.Sprintf("%s %d custom %T", name, j, callable)
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Summary returns a one-line summary of the given mock, listing the number of
+// calls made to each method for which expectations were registered, out of
+// the number of calls expected, followed by + if more calls are allowed.
+// Methods are sorted by name.  A method that is being called when the summary
+// is made is listed as in call, so that Summary may be used by a delegate.
+// Summary is called by the String method generated by vermockgen.
+func Summary[T any](key *T) string {
+	mock := lookup(key)
+	if mock == nil {
+		return fmt.Sprintf("%T (not found)", key)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%T{", key)
+	names, delegates := sortedDelegates(mock)
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		delegate := delegates[i]
+		if !delegate.TryLock() {
+			fmt.Fprintf(&buf, "%s: in call", name)
+			continue
+		}
+		fmt.Fprintf(&buf, "%s: %d/%d", name, delegate.callCount, delegate.minCalls())
+		if delegate.MultiCallable() {
+			buf.WriteString("+")
+		}
+		delegate.Unlock()
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// sortedDelegates returns the names of the delegates of the given mock, sorted,
+// and the delegates in the same order.
+func sortedDelegates(mock *mock) ([]string, []*Delegate) {
+	mock.Lock()
+	defer mock.Unlock()
+	names := make([]string, 0, len(mock.Delegates))
+	for name := range mock.Delegates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	delegates := make([]*Delegate, len(names))
+	for i, name := range names {
+		delegates[i] = mock.Delegates[name]
+	}
+	return names, delegates
+}
+
+// dumpOrdered returns the ordinal of an ordered call as written by
+// DumpExpectations.
+func dumpOrdered(o ordered) string {
+	if !o.inOrder {
+		return ""
+	}
+	return fmt.Sprintf(" ordered %d", o.ordinal)
+}
+
+// AssertExpectationsMatch asserts that the expectations of the given mock, as
+// written by DumpExpectations, match the golden representation read from r.
+func AssertExpectationsMatch[T any](t testing.TB, key *T, r io.Reader) {
+	t.Helper()
+
+	golden, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read golden expectations: %v", err)
+	}
+	var got bytes.Buffer
+	if err := DumpExpectations(key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), golden) {
+		t.Errorf("expectations do not match golden:\ngot:\n%s\nwant:\n%s", got.Bytes(), golden)
+	}
+}
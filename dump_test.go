@@ -0,0 +1,74 @@
+package vermock_test
+
+import (
+	"strings"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestDumpExpectations(t *testing.T) {
+	cache := vermock.New(&testing.T{},
+		vermock.Expect[mockCache]("Put", func(key string, value any) error {
+			return nil
+		}),
+		vermock.ExpectInOrder(
+			vermock.Expect[mockCache]("Get", func(t testing.TB, key string) (any, bool) {
+				return nil, false
+			}),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		),
+		vermock.ExpectMany[mockCache]("Load", func(n vermock.CallCount, keys ...string) {}),
+		vermock.ExpectPanic[mockCache]("Delete", "boom"),
+	)
+
+	const golden = `Delete 0 once func(string) ordered 2
+Delete 1 panic boom
+Get 0 once func(testing.TB, string) (interface {}, bool) ordered 1
+Load 0 many func(vermock.CallCount, ...string)
+Put 0 once func(string, interface {}) error
+`
+	var b strings.Builder
+	if err := vermock.DumpExpectations(cache, &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != golden {
+		t.Errorf("unexpected dump:\n%s", b.String())
+	}
+	vermock.AssertExpectationsMatch(t, cache, strings.NewReader(golden))
+
+	mockT := &testing.T{}
+	vermock.AssertExpectationsMatch(mockT, cache, strings.NewReader("Put 0 once func()\n"))
+	if !mockT.Failed() {
+		t.Error("expected failure for mismatched expectations")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	var inCall string
+	var cache *mockCache
+	cache = vermock.New(t,
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+		vermock.Expect[mockCache]("Delete", func(key string) {
+			inCall = vermock.Summary(cache)
+		}),
+		vermock.ExpectMany[mockCache]("Load", func(keys ...string) {}),
+	)
+	const want = "*vermock_test.mockCache{Delete: 0/2, Load: 0/1+}"
+	if got := vermock.Summary(cache); got != want {
+		t.Errorf("expected summary %q, got %q", want, got)
+	}
+
+	cache.Delete("foo")
+	cache.Delete("foo")
+	const wantInCall = "*vermock_test.mockCache{Delete: in call, Load: 0/1+}"
+	if inCall != wantInCall {
+		t.Errorf("expected summary in call %q, got %q", wantInCall, inCall)
+	}
+	cache.Load("foo")
+	cache.Load("bar")
+	const wantAfter = "*vermock_test.mockCache{Delete: 2/2, Load: 2/1+}"
+	if got := vermock.Summary(cache); got != wantAfter {
+		t.Errorf("expected summary %q, got %q", wantAfter, got)
+	}
+}
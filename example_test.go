@@ -15,11 +15,9 @@ type Cache interface {
 	Load(...string)
 }
 
-// mockCache is a mock implementation of Cache.  It can be anything, but
-// zero-sized types are problematic.
-type mockCache struct {
-	_ byte // prevent zero-sized type
-}
+// mockCache is a mock implementation of Cache.  It can be anything, even an
+// empty struct.
+type mockCache struct{}
 
 // Put returns one value, so use vermock.Call1.
 func (m *mockCache) Put(key string, value any) error {
@@ -272,6 +270,29 @@ func Example_mixedOrderedCalls() {
 	// less than expected: false
 }
 
+func Example_argumentMatchers() {
+	t := &exampleT{} // or any testing.TB, your test does not create this
+	// 1. Create a mock object with Match.
+	var cache Cache = vermock.New(t,
+		vermock.Match[mockCache]("Put", vermock.Eq("foo"), vermock.AnyOf("bar", "baz")),
+		vermock.ExpectMany[mockCache]("Put", func(key string, value any) error {
+			return nil
+		}),
+	)
+	// 2. Use the mock object in your code under test.
+	cache.Put("foo", "bar")
+	cache.Put("foo", "qux")
+	// 3. Assert that all expected methods were called.
+	vermock.AssertExpectedCalls(t, cache)
+	// mock will fail the test because the second call to Put has an
+	// unexpected value.
+	fmt.Println("unexpected argument:", t.Failed())
+	// Output:
+	// call to Put: 0/0
+	// argument 2 to Put did not match: expected AnyOf("bar", "baz"), got "qux"
+	// unexpected argument: true
+}
+
 var _ testing.TB = &exampleT{}
 
 type exampleT struct {
@@ -0,0 +1,77 @@
+package vermock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExpectExactlyOneOf registers the given options as alternatives, of which
+// exactly one is expected to be called.  This models mutually exclusive code
+// paths.  AssertExpectedCalls marks the test as failed if none or more than one
+// of the methods registered by the options were called, and otherwise only
+// checks the expectations of the method that was called.
+func ExpectExactlyOneOf[T any](options ...Option[T]) Option[T] {
+	return func(key *T) {
+		mock := lookup(key)
+		before := delegateLens(mock)
+		for _, option := range options {
+			option(key)
+		}
+		var group []string
+		for name, n := range delegateLens(mock) {
+			if n > before[name] {
+				group = append(group, name)
+			}
+		}
+		sort.Strings(group)
+		mock.Lock()
+		defer mock.Unlock()
+		mock.exclusive = append(mock.exclusive, group)
+	}
+}
+
+// delegateLens returns the number of Callables of each Delegate of the mock.
+func delegateLens(mock *mock) map[string]int {
+	mock.Lock()
+	defer mock.Unlock()
+	lens := make(map[string]int, len(mock.Delegates))
+	for name, delegate := range mock.Delegates {
+		delegate.Lock()
+		lens[name] = delegate.Len()
+		delegate.Unlock()
+	}
+	return lens
+}
+
+// exclusiveFailures checks the groups registered with ExpectExactlyOneOf.  It
+// returns the names of the methods in the groups that were not called, which
+// are exempt from the usual expectations, along with the failures of the
+// groups.
+func exclusiveFailures(mock *mock) (uncalled map[string]bool, failures []string) {
+	mock.Lock()
+	defer mock.Unlock()
+	uncalled = make(map[string]bool)
+	for _, group := range mock.exclusive {
+		var called []string
+		for _, name := range group {
+			delegate := mock.Delegates[name]
+			delegate.Lock()
+			count := delegate.callCount
+			delegate.Unlock()
+			if count > 0 {
+				called = append(called, name)
+			} else {
+				uncalled[name] = true
+			}
+		}
+		switch len(called) {
+		case 0:
+			failures = append(failures, fmt.Sprintf("failed to make call to exactly one of %s", strings.Join(group, ", ")))
+		case 1:
+		default:
+			failures = append(failures, fmt.Sprintf("expected call to exactly one of %s: got calls to %s", strings.Join(group, ", "), strings.Join(called, ", ")))
+		}
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package vermock_test
+
+import (
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestExpectExactlyOneOf(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		put    bool
+		delete bool
+		failed bool
+	}{
+		{name: "neither", failed: true},
+		{name: "put", put: true},
+		{name: "delete", delete: true},
+		{name: "both", put: true, delete: true, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			cache := vermock.New(mockT,
+				vermock.ExpectExactlyOneOf(
+					vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+					vermock.Expect[mockCache]("Delete", func(key string) {}),
+				),
+			)
+			if tc.put {
+				_ = cache.Put("foo", "bar")
+			}
+			if tc.delete {
+				cache.Delete("foo")
+			}
+			vermock.AssertExpectedCalls(mockT, cache)
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
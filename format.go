@@ -0,0 +1,112 @@
+package vermock
+
+import "fmt"
+
+// FailureKind identifies the kind of a Failure.
+type FailureKind int
+
+const (
+	// UnexpectedCall is the kind of a call to a method that has no remaining
+	// expectations.
+	UnexpectedCall FailureKind = iota
+	// OutOfOrderCall is the kind of an ordered call that was made out of
+	// order.
+	OutOfOrderCall
+	// MissingCall is the kind of an expected call that was not made, as
+	// reported by AssertExpectedCalls.
+	MissingCall
+	// UnreachedOrderedCall is the kind of an ordered call that was not made
+	// because the ordered calls never reached it, as reported by
+	// AssertExpectedCalls.
+	UnreachedOrderedCall
+)
+
+// Failure describes a fail of a mock, for a FailureFormatter to format.
+type Failure struct {
+	// Kind is the kind of the fail.
+	Kind FailureKind
+	// Name is the name of the method.
+	Name string
+	// Args are the arguments of an UnexpectedCall.
+	Args []any
+	// Reason is why an UnexpectedCall was unexpected.
+	Reason FailureReason
+	// Expected is the expected ordinal of an OutOfOrderCall, the minimum
+	// number of calls of a MissingCall, or the ordinal of an
+	// UnreachedOrderedCall.
+	Expected int
+	// Got is the ordinal of an OutOfOrderCall, the number of calls of a
+	// MissingCall, or the ordinal of the last ordered call of an
+	// UnreachedOrderedCall.
+	Got int
+	// Description describes the next expected call of a MissingCall, as
+	// registered with Describe or Match, if any.
+	Description string
+	// ExpectedName is the name of the method of the ordered expectation at
+	// the ordinal of an OutOfOrderCall, if any.
+	ExpectedName string
+}
+
+// FailureFormatter formats the message of a Failure.
+type FailureFormatter func(Failure) string
+
+// DefaultFailureFormatter is the FailureFormatter of a mock that was not
+// created with WithFailureFormatter.
+func DefaultFailureFormatter(f Failure) string {
+	switch f.Kind {
+	case UnexpectedCall:
+		return (&UnexpectedCallError{Name: f.Name, Args: f.Args, Reason: f.Reason}).Error()
+	case OutOfOrderCall:
+		if f.ExpectedName != "" {
+			return fmt.Sprintf("out of order: expected %s here but %s was called", f.ExpectedName, f.Name)
+		}
+		return fmt.Sprintf("out of order call to %s: expected %d, got %d", f.Name, f.Expected, f.Got)
+	case UnreachedOrderedCall:
+		return fmt.Sprintf("ordered call %d to %s was never reached: only got %d ordered calls", f.Expected, f.Name, f.Got)
+	}
+	var msg string
+	switch f.Got {
+	case 0:
+		if f.Expected > 1 {
+			msg = fmt.Sprintf("failed to make call to %s: expected %d calls, got none", f.Name, f.Expected)
+		} else {
+			msg = fmt.Sprintf("failed to make call to %s: expected one call, got none", f.Name)
+		}
+	case 1:
+		msg = fmt.Sprintf("failed to make call to %s: only got one call", f.Name)
+	default:
+		msg = fmt.Sprintf("failed to make call to %s: only got %d calls", f.Name, f.Got)
+	}
+	if f.Description != "" {
+		msg += fmt.Sprintf(": expected call %d/%d to %s", f.Got+1, f.Expected, f.Description)
+	}
+	return msg
+}
+
+// WithFailureFormatter makes the mock format the messages of its unexpected,
+// out of order and missing calls with the given FailureFormatter, rather
+// than with DefaultFailureFormatter.
+func WithFailureFormatter[T any](formatter FailureFormatter) Option[T] {
+	return func(key *T) {
+		lookup(key).formatter = formatter
+	}
+}
+
+// format returns the message of the given Failure of the mock.
+func (m *mock) format(f Failure) string {
+	if m.formatter == nil {
+		return DefaultFailureFormatter(f)
+	}
+	return m.formatter(f)
+}
+
+// failureError is an error with a message formatted by a FailureFormatter,
+// which wraps the error that describes the same fail.
+type failureError struct {
+	msg string
+	err error
+}
+
+func (e *failureError) Error() string { return e.msg }
+
+func (e *failureError) Unwrap() error { return e.err }
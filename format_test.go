@@ -0,0 +1,102 @@
+package vermock_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func formatFailure(f vermock.Failure) string {
+	return fmt.Sprintf("kind %d of %s: %d/%d", f.Kind, f.Name, f.Got, f.Expected)
+}
+
+func TestWithFailureFormatter(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.WithFailureFormatter[mockCache](formatFailure),
+		vermock.ExpectInOrder(
+			vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		),
+	)
+	_, err := vermock.TryCall(cache, "Delete", nil, reflect.ValueOf("foo"))
+	if want := "kind 1 of Delete: 1/2"; err == nil || err.Error() != want {
+		t.Errorf("unexpected out of order error: expected %q, got %v", want, err)
+	}
+	_, err = vermock.TryCall(cache, "Delete", nil, reflect.ValueOf("foo"))
+	var unexpected *vermock.UnexpectedCallError
+	if want := "kind 0 of Delete: 0/0"; err == nil || err.Error() != want {
+		t.Errorf("unexpected error: expected %q, got %v", want, err)
+	} else if !errors.As(err, &unexpected) {
+		t.Errorf("expected *UnexpectedCallError, got %T", err)
+	}
+
+	cache = vermock.New(t,
+		vermock.WithFailureFormatter[mockCache](func(f vermock.Failure) string {
+			return fmt.Sprintf("kind %d of %s: %v", f.Kind, f.Name, f.Reason)
+		}),
+		vermock.ExpectAtMost[mockCache]("Delete", 1, func(key string) {}),
+	)
+	cache.Delete("foo")
+	_, err = vermock.TryCall(cache, "Delete", nil, reflect.ValueOf("foo"))
+	if want := "kind 0 of Delete: too many calls"; err == nil || err.Error() != want {
+		t.Errorf("unexpected too many calls error: expected %q, got %v", want, err)
+	}
+	cache = vermock.New(t,
+		vermock.WithFailureFormatter[mockCache](formatFailure),
+		vermock.ExpectTimes[mockCache]("Get", 2, func(key string) (any, bool) { return nil, false }),
+	)
+	cache.Get("foo")
+	defer func() {
+		if r, want := recover(), "kind 2 of Get: 1/2"; r != want {
+			t.Errorf("unexpected failure: expected %q, got %v", want, r)
+		}
+	}()
+	vermock.AssertExpectedCallsWith(t, []vermock.AssertOption{vermock.WithPanicOnFail()}, cache)
+}
+
+func TestDefaultFailureFormatter(t *testing.T) {
+	for _, tc := range []struct {
+		failure vermock.Failure
+		want    string
+	}{
+		{
+			failure: vermock.Failure{Kind: vermock.UnexpectedCall, Name: "Get", Reason: vermock.NoExpectationsLeft},
+			want:    "unexpected call to Get: no expectations left",
+		},
+		{
+			failure: vermock.Failure{Kind: vermock.OutOfOrderCall, Name: "Get", Expected: 2, Got: 1},
+			want:    "out of order call to Get: expected 2, got 1",
+		},
+		{
+			failure: vermock.Failure{Kind: vermock.OutOfOrderCall, Name: "Get", Expected: 2, Got: 1, ExpectedName: "Put"},
+			want:    "out of order: expected Put here but Get was called",
+		},
+		{
+			failure: vermock.Failure{Kind: vermock.MissingCall, Name: "Get", Expected: 1},
+			want:    "failed to make call to Get: expected one call, got none",
+		},
+		{
+			failure: vermock.Failure{Kind: vermock.MissingCall, Name: "Get", Expected: 3},
+			want:    "failed to make call to Get: expected 3 calls, got none",
+		},
+		{
+			failure: vermock.Failure{Kind: vermock.MissingCall, Name: "Get", Expected: 2, Got: 1},
+			want:    "failed to make call to Get: only got one call",
+		},
+		{
+			failure: vermock.Failure{Kind: vermock.MissingCall, Name: "Get", Expected: 3, Got: 2, Description: `Get("foo")`},
+			want:    `failed to make call to Get: only got 2 calls: expected call 3/3 to Get("foo")`,
+		},
+		{
+			failure: vermock.Failure{Kind: vermock.UnreachedOrderedCall, Name: "Get", Expected: 3, Got: 2},
+			want:    "ordered call 3 to Get was never reached: only got 2 ordered calls",
+		},
+	} {
+		if got := vermock.DefaultFailureFormatter(tc.failure); got != tc.want {
+			t.Errorf("unexpected message: expected %q, got %q", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package vermock
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// FuncName is the name of the method that the calls of a mock function
+// created by NewFunc are dispatched as, so that options such as ExpectMany,
+// Match or WithDefault, and helpers such as CallCountOf, may be given
+// FuncName to apply to the function.
+const FuncName = "func"
+
+// NewFunc creates a mock function of type F, which must be a function type,
+// and applies the given options.  It returns the key of the mock, which
+// holds the function: each call of the function calls the next delegate
+// registered for FuncName, like a method of a mock created by New.  The key
+// may be given to AssertExpectedCalls.
+// Panics if F is not a function type.
+func NewFunc[F any](t testing.TB, opts ...Option[F]) *F {
+	funcType := reflect.TypeOf((*F)(nil)).Elem()
+	if funcType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("vermock.NewFunc: expected function type, got %s", funcType))
+	}
+	key := New[F](t)
+	fn := reflect.MakeFunc(funcType, func(in []reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, funcType.NumOut())
+		for i := range out {
+			out[i] = reflect.New(funcType.Out(i))
+		}
+		doCall(key, FuncName, in, out)
+		for i := range out {
+			out[i] = out[i].Elem()
+		}
+		return out
+	})
+	reflect.ValueOf(key).Elem().Set(fn)
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(key)
+	}
+	return key
+}
+
+// ExpectFunc registers a function to be called exactly once when the mock
+// function created by NewFunc is called.  Like Expect, the arguments of fn
+// must match those of F and may optionally be preceded by a testing.TB or
+// *testing.T and a CallCount.
+// Panics if fn does not match the signature of F.
+func ExpectFunc[F any](fn any) Option[F] {
+	funcType := reflect.TypeOf((*F)(nil)).Elem()
+	if !delegateMatches(reflect.TypeOf(fn), funcType) {
+		panic(fmt.Sprintf("vermock.ExpectFunc: expected %s, got %T", funcType, fn))
+	}
+	return Expect[F](FuncName, fn)
+}
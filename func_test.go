@@ -0,0 +1,82 @@
+package vermock_test
+
+import (
+	"errors"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+type fetchFunc func(url string) ([]byte, error)
+
+func TestNewFunc(t *testing.T) {
+	failure := errors.New("unavailable")
+	fetch := vermock.NewFunc(t,
+		vermock.ExpectFunc[fetchFunc](func(url string) ([]byte, error) {
+			if url != "http://example.com" {
+				t.Errorf("unexpected url: %q", url)
+			}
+			return []byte("ok"), nil
+		}),
+		vermock.ExpectFunc[fetchFunc](func(_ testing.TB, n vermock.CallCount, url string) ([]byte, error) {
+			if n != 1 {
+				t.Errorf("unexpected call count: %d", n)
+			}
+			return nil, failure
+		}),
+	)
+
+	if body, err := (*fetch)("http://example.com"); string(body) != "ok" || err != nil {
+		t.Errorf("unexpected result: %q, %v", body, err)
+	}
+	if body, err := (*fetch)("http://example.com"); body != nil || err != failure {
+		t.Errorf("unexpected result: %q, %v", body, err)
+	}
+	if n := vermock.CallCountOf(fetch, vermock.FuncName); n != 2 {
+		t.Errorf("expected 2 calls, got %d", n)
+	}
+	vermock.AssertExpectedCalls(t, fetch)
+}
+
+func TestNewFunc_unexpected(t *testing.T) {
+	mockT := &testing.T{}
+	fetch := vermock.NewFunc[fetchFunc](mockT)
+	if _, err := (*fetch)("http://example.com"); err == nil {
+		t.Error("expected error")
+	}
+	if !mockT.Failed() {
+		t.Error("expected failure")
+	}
+}
+
+func TestNewFunc_variadic(t *testing.T) {
+	var got []string
+	join := vermock.NewFunc(t,
+		vermock.ExpectMany[func(...string)](vermock.FuncName, func(parts ...string) {
+			got = append(got, parts...)
+		}),
+	)
+	(*join)("a", "b")
+	(*join)()
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("unexpected parts: %v", got)
+	}
+}
+
+func TestNewFunc_invalid(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "vermock.NewFunc: expected function type, got string" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	vermock.NewFunc[string](t)
+}
+
+func TestExpectFunc_invalid(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "vermock.ExpectFunc: expected vermock_test.fetchFunc, got func(string) error" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	vermock.ExpectFunc[fetchFunc](func(url string) error { return nil })
+}
@@ -1,12 +1,55 @@
 package vermock
 
-import "testing"
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// AssertOption configures the behaviour of AssertExpectedCallsWith.
+type AssertOption func(*assertOptions)
+
+// assertOptions holds the options for AssertExpectedCalls.
+type assertOptions struct {
+	panicOnFail bool
+}
+
+// WithPanicOnFail returns an AssertOption that makes AssertExpectedCallsWith
+// panic with the aggregated failure message instead of calling t.Error.
+func WithPanicOnFail() AssertOption {
+	return func(opts *assertOptions) {
+		opts.panicOnFail = true
+	}
+}
 
 // AssertExpectedCalls asserts that all expected callables of all delegates of
-// the given mocks were called.
+// the given mocks were called.  The callable registered by ExpectMany must be
+// called at least once, while those registered by ExpectAtMost and
+// ExpectBetween must be called at least their minimum number of times.  When
+// the next callable of a delegate was registered by ExpectInOrder or
+// ExpectInSequence, and the ordered calls never reached it, the failure names
+// its ordinal instead of the number of calls.
 func AssertExpectedCalls(t testing.TB, mocks ...any) {
 	t.Helper()
+	AssertExpectedCallsWith(t, nil, mocks...)
+}
 
+// AssertExpectedCallsWith is like AssertExpectedCalls, except that its
+// behaviour is configured by the given AssertOptions.
+func AssertExpectedCallsWith(t testing.TB, opts []AssertOption, mocks ...any) {
+	t.Helper()
+
+	var options assertOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	var failures []string
 	for _, key := range mocks {
 		if key == nil {
 			continue
@@ -17,23 +60,267 @@ func AssertExpectedCalls(t testing.TB, mocks ...any) {
 			continue
 		}
 
-		mock, ok := registry[key]
-		if !ok {
+		mock := lookup(key)
+		if mock == nil {
 			t.Fatalf("mock not found: %T", key)
 		}
 
-		for name, delegate := range mock.Delegates {
-			if count := delegate.callCount; int(count) < delegate.Len() {
-				if count == 0 {
-					t.Errorf("failed to make call to %s", name)
-				} else if count == 1 {
-					t.Errorf("failed to make call to %s: only got one call", name)
-				} else {
-					t.Errorf("failed to make call to %s: only got %d calls", name, count)
-				}
+		uncalled, groupFailures := exclusiveFailures(mock)
+		failures = append(failures, groupFailures...)
+
+		for name, delegate := range allDelegates(mock) {
+			if uncalled[name] {
+				continue
+			}
+			if failure, ok := missingCall(name, delegate); ok {
+				failures = append(failures, mock.format(failure))
 			}
 		}
 	}
+
+	if options.panicOnFail && len(failures) > 0 {
+		panic(strings.Join(failures, "\n"))
+	}
+	for _, failure := range failures {
+		t.Error(failure)
+	}
+}
+
+// missingCall returns the Failure of the delegate of the method with the given
+// name, if it was called fewer times than expected.  The delegate is locked
+// while it is inspected.
+func missingCall(name string, delegate *Delegate) (Failure, bool) {
+	delegate.Lock()
+	defer delegate.Unlock()
+	count := delegate.callCount
+	if int(count) >= delegate.minCalls() {
+		return Failure{}, false
+	}
+	failure := Failure{Kind: MissingCall, Name: name, Expected: delegate.minCalls(), Got: int(count)}
+	if ordinal, calls, ok := unreachedOrdinal(delegate); ok {
+		failure = Failure{Kind: UnreachedOrderedCall, Name: name, Expected: int(ordinal), Got: int(calls)}
+	} else if description, ok := delegate.describe(name, int(count)); ok {
+		failure.Description = description
+	}
+	return failure, true
+}
+
+// unreachedOrdinal returns the ordinal of the next callable of the delegate
+// and that of the last ordered call, if the next callable is ordered and the
+// ordered calls have not reached it.  The delegate must be locked.
+func unreachedOrdinal(delegate *Delegate) (ordinal, calls uint, ok bool) {
+	if int(delegate.callCount) >= delegate.Len() {
+		return
+	}
+	o, ok := orderedOf(delegate.Callables[delegate.callCount])
+	if !ok {
+		return
+	}
+	reached, calls := o.reached()
+	return o.ordinal, calls, !reached
+}
+
+// TotalCalls returns the total number of calls made to all delegates of the
+// given mock.  It returns 0 if the mock is not found.
+func TotalCalls[T any](key *T) (total int) {
+	mock := lookup(key)
+	if mock == nil {
+		return
+	}
+	mock.Lock()
+	defer mock.Unlock()
+	for _, delegate := range mock.Delegates {
+		delegate.Lock()
+		total += int(delegate.callCount)
+		delegate.Unlock()
+	}
+	return
+}
+
+// AssertTotalCalls asserts that exactly n calls were made across all
+// delegates of the given mock.
+func AssertTotalCalls[T any](t testing.TB, key *T, n int) {
+	t.Helper()
+
+	if total := TotalCalls(key); total != n {
+		t.Errorf("unexpected total number of calls: expected %d, got %d", n, total)
+	}
+}
+
+// CallCountOf returns the number of calls made to the method with the given
+// name of the given mock.  It returns 0 if the mock is not found or no
+// expectations were registered for the method.
+func CallCountOf[T any](key *T, name string) int {
+	mock := lookup(key)
+	if mock == nil {
+		return 0
+	}
+	mock.Lock()
+	delegate, ok := mock.Delegates[name]
+	mock.Unlock()
+	if !ok {
+		return 0
+	}
+	delegate.Lock()
+	defer delegate.Unlock()
+	return int(delegate.callCount)
+}
+
+// HasDelegate reports whether any expectations, or a default, were registered
+// for the method with the given name of the given mock, whether or not they
+// have been called.  It returns false if the mock is not found.  Generated
+// mocks that forward to a real implementation use HasDelegate to decide
+// whether to call the real implementation instead of the mock.
+func HasDelegate[T any](key *T, name string) bool {
+	mock := lookup(key)
+	if mock == nil {
+		return false
+	}
+	mock.Lock()
+	delegate, ok := mock.Delegates[name]
+	mock.Unlock()
+	if !ok {
+		return false
+	}
+	delegate.Lock()
+	defer delegate.Unlock()
+	return delegate.Len() > 0 || delegate.fallback != nil
+}
+
+// Peek returns the Callable that the next call of the method with the given
+// name of the given mock would invoke, without consuming it: the next
+// expectation, the last expectation when it is MultiCallable, or otherwise
+// the default registered with WithDefault.  It returns false if the mock is
+// not found, or the next call would be marked as a fail.
+func Peek[T any](key *T, name string) (Callable, bool) {
+	mock := lookup(key)
+	if mock == nil {
+		return nil, false
+	}
+	mock.Lock()
+	delegate, ok := mock.Delegates[name]
+	mock.Unlock()
+	if !ok {
+		return nil, false
+	}
+	delegate.Lock()
+	defer delegate.Unlock()
+	count := int(delegate.callCount)
+	switch max, bounded := delegate.maxCalls(); {
+	case count < delegate.Len():
+		return delegate.Callables[count], true
+	case bounded && count >= max:
+		return nil, false
+	case delegate.MultiCallable():
+		return delegate.last(), true
+	case delegate.fallback != nil:
+		return delegate.fallback, true
+	}
+	return nil, false
+}
+
+// AssertCalled asserts that the method with the given name of the given mock
+// was called at least once.
+func AssertCalled[T any](t testing.TB, key *T, name string) {
+	t.Helper()
+
+	if CallCountOf(key, name) == 0 {
+		t.Errorf("expected call to %s: got no calls", name)
+	}
+}
+
+// AssertNotCalled asserts that the method with the given name of the given
+// mock was not called.
+func AssertNotCalled[T any](t testing.TB, key *T, name string) {
+	t.Helper()
+
+	if n := CallCountOf(key, name); n > 0 {
+		t.Errorf("expected no calls to %s: got %d", name, n)
+	}
+}
+
+// ResetMethod clears the expectations, including any default, and call count
+// of the method with the given name of the given mock, leaving the
+// expectations of all other methods intact.  New expectations for the method
+// may be registered by applying an Option to the mock, e.g.
+// Expect[T](name, fn)(key).  It does nothing if the mock is not found.
+func ResetMethod[T any](key *T, name string) {
+	mock := lookup(key)
+	if mock == nil {
+		return
+	}
+	delegate := delegateByName(mock, name)
+	delegate.Lock()
+	defer delegate.Unlock()
+	delegate.Callables = nil
+	delegate.callCount = 0
+	delegate.callTimes = nil
+	delegate.descriptions = nil
+	delegate.fallback = nil
+}
+
+// Override replaces the remaining expectations of the method with the given
+// name of the given mock by fn, which is called for all further calls of the
+// method, while keeping the call count and the expectations already called.
+// This may be used mid-test, for example to make a method fail after a
+// number of successful calls.  Unlike ExpectMany, fn need not be called.
+// Like ExpectMany, the arguments of fn must match the named method signature
+// and may optionally be preceded by a testing.TB or *testing.T and a
+// CallCount.  It does nothing if the mock is not found.
+// Panics if fn is not a function.
+func Override[T any](key *T, name string, fn any) {
+	if funcType := reflect.TypeOf(fn); funcType == nil || funcType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("vermock.Override: expected function, got %T", fn))
+	}
+	mock := lookup(key)
+	if mock == nil {
+		return
+	}
+	delegate := delegateByName(mock, name)
+	delegate.Lock()
+	defer delegate.Unlock()
+	if int(delegate.callCount) < delegate.Len() {
+		delegate.Callables = delegate.Callables[:delegate.callCount]
+	}
+	delegate.Callables = delegate.Callables.Append(bounded{
+		multi: multi{Value: reflect.ValueOf(fn)},
+		min:   0,
+		max:   math.MaxInt,
+	})
+}
+
+// Reset clears the expectations, call counts and call log of the given
+// mock, so that the mock may be reused, for example across the cases of a
+// table-driven test.  Expectations must be registered again afterwards by
+// applying Options to the mock, e.g. Expect[T](name, fn)(key).  Options that
+// configure the mock, such as WithStrict, are not reset.  It does nothing if
+// the mock is not found.
+func Reset[T any](key *T) {
+	mock := lookup(key)
+	if mock == nil {
+		return
+	}
+	mock.Lock()
+	defer mock.Unlock()
+	mock.Delegates = Delegates{}
+	mock.prefixes = nil
+	mock.exclusive = nil
+	mock.ordered = ordered{seq: NewSequence()}
+	mock.logMu.Lock()
+	mock.log = nil
+	mock.lastCall = time.Time{}
+	mock.logMu.Unlock()
+}
+
+// Close removes the given mock from the registry immediately, rather than
+// when the test completes, so that a test that creates many mocks need not
+// keep them all.  Any further use of the mock panics, and its expected calls
+// are no longer asserted by AutoAssert, so AssertExpectedCalls should be
+// called before Close.  It is safe to call Close more than once.
+func Close[T any](key *T) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, key)
 }
 
 // Call0 calls the function of the given name for the given mock with the
@@ -42,7 +329,7 @@ func AssertExpectedCalls(t testing.TB, mocks ...any) {
 // to return no result values, otherwise the will be marked as a fail and this
 // function will panic.
 func Call0[T any](key *T, name string, in ...any) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	CallDelegate(key, name, nil, toValues(in...)...)
 }
 
@@ -53,7 +340,7 @@ func Call0[T any](key *T, name string, in ...any) {
 // function will return an error when T1 is assignable to an error type, or
 // this function will panic.
 func Call1[T1, T any](key *T, name string, in ...any) (v T1) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v))
 	return
 }
@@ -65,7 +352,7 @@ func Call1[T1, T any](key *T, name string, in ...any) (v T1) {
 // function will return an error when T2 is assignable to an error type, or
 // this function will panic.
 func Call2[T1, T2, T any](key *T, name string, in ...any) (v1 T1, v2 T2) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2))
 	return
 }
@@ -77,7 +364,7 @@ func Call2[T1, T2, T any](key *T, name string, in ...any) (v1 T1, v2 T2) {
 // this function will return an error when T3 is assignable to an error type,
 // or this function will panic.
 func Call3[T1, T2, T3, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3))
 	return
 }
@@ -89,7 +376,7 @@ func Call3[T1, T2, T3, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3
 // this function will return an error when T4 is assignable to an error type,
 // or this function will panic.
 func Call4[T1, T2, T3, T4, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4))
 	return
 }
@@ -101,7 +388,7 @@ func Call4[T1, T2, T3, T4, T any](key *T, name string, in ...any) (v1 T1, v2 T2,
 // function will return an error when T5 is assignable to an error type, or
 // this function will panic.
 func Call5[T1, T2, T3, T4, T5, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5))
 	return
 }
@@ -113,7 +400,7 @@ func Call5[T1, T2, T3, T4, T5, T any](key *T, name string, in ...any) (v1 T1, v2
 // function will return an error when T6 is assignable to an error type, or
 // this function will panic.
 func Call6[T1, T2, T3, T4, T5, T6, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6))
 	return
 }
@@ -125,7 +412,7 @@ func Call6[T1, T2, T3, T4, T5, T6, T any](key *T, name string, in ...any) (v1 T1
 // function will return an error when T7 is assignable to an error type, or
 // this function will panic.
 func Call7[T1, T2, T3, T4, T5, T6, T7, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7))
 	return
 }
@@ -137,7 +424,7 @@ func Call7[T1, T2, T3, T4, T5, T6, T7, T any](key *T, name string, in ...any) (v
 // function will return an error when T8 is assignable to an error type, or
 // this function will panic.
 func Call8[T1, T2, T3, T4, T5, T6, T7, T8, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8))
 	return
 }
@@ -149,7 +436,91 @@ func Call8[T1, T2, T3, T4, T5, T6, T7, T8, T any](key *T, name string, in ...any
 // function will return an error when T9 is assignable to an error type, or
 // this function will panic.
 func Call9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9) {
-	registry[key].Helper()
+	lookup(key).Helper()
 	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9))
 	return
 }
+
+// Call10 calls the function of the given name for the given mock with the
+// given arguments.  If the function is variadic then the last argument must be
+// passed as a slice, otherwise this function panics.  The function is expected
+// to return 10 result values, otherwise the will be marked as a fail and this
+// function will return an error when T10 is assignable to an error type, or
+// this function will panic.
+func Call10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9, v10 T10) {
+	lookup(key).Helper()
+	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9, &v10))
+	return
+}
+
+// Call11 calls the function of the given name for the given mock with the
+// given arguments.  If the function is variadic then the last argument must be
+// passed as a slice, otherwise this function panics.  The function is expected
+// to return 11 result values, otherwise the will be marked as a fail and this
+// function will return an error when T11 is assignable to an error type, or
+// this function will panic.
+func Call11[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9, v10 T10, v11 T11) {
+	lookup(key).Helper()
+	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9, &v10, &v11))
+	return
+}
+
+// Call12 calls the function of the given name for the given mock with the
+// given arguments.  If the function is variadic then the last argument must be
+// passed as a slice, otherwise this function panics.  The function is expected
+// to return 12 result values, otherwise the will be marked as a fail and this
+// function will return an error when T12 is assignable to an error type, or
+// this function will panic.
+func Call12[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9, v10 T10, v11 T11, v12 T12) {
+	lookup(key).Helper()
+	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9, &v10, &v11, &v12))
+	return
+}
+
+// Call13 calls the function of the given name for the given mock with the
+// given arguments.  If the function is variadic then the last argument must be
+// passed as a slice, otherwise this function panics.  The function is expected
+// to return 13 result values, otherwise the will be marked as a fail and this
+// function will return an error when T13 is assignable to an error type, or
+// this function will panic.
+func Call13[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9, v10 T10, v11 T11, v12 T12, v13 T13) {
+	lookup(key).Helper()
+	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9, &v10, &v11, &v12, &v13))
+	return
+}
+
+// Call14 calls the function of the given name for the given mock with the
+// given arguments.  If the function is variadic then the last argument must be
+// passed as a slice, otherwise this function panics.  The function is expected
+// to return 14 result values, otherwise the will be marked as a fail and this
+// function will return an error when T14 is assignable to an error type, or
+// this function will panic.
+func Call14[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9, v10 T10, v11 T11, v12 T12, v13 T13, v14 T14) {
+	lookup(key).Helper()
+	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9, &v10, &v11, &v12, &v13, &v14))
+	return
+}
+
+// Call15 calls the function of the given name for the given mock with the
+// given arguments.  If the function is variadic then the last argument must be
+// passed as a slice, otherwise this function panics.  The function is expected
+// to return 15 result values, otherwise the will be marked as a fail and this
+// function will return an error when T15 is assignable to an error type, or
+// this function will panic.
+func Call15[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T15, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9, v10 T10, v11 T11, v12 T12, v13 T13, v14 T14, v15 T15) {
+	lookup(key).Helper()
+	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9, &v10, &v11, &v12, &v13, &v14, &v15))
+	return
+}
+
+// Call16 calls the function of the given name for the given mock with the
+// given arguments.  If the function is variadic then the last argument must be
+// passed as a slice, otherwise this function panics.  The function is expected
+// to return 16 result values, otherwise the will be marked as a fail and this
+// function will return an error when T16 is assignable to an error type, or
+// this function will panic.
+func Call16[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T15, T16, T any](key *T, name string, in ...any) (v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8, v9 T9, v10 T10, v11 T11, v12 T12, v13 T13, v14 T14, v15 T15, v16 T16) {
+	lookup(key).Helper()
+	doCall(key, name, toValues(in...), toValues(&v1, &v2, &v3, &v4, &v5, &v6, &v7, &v8, &v9, &v10, &v11, &v12, &v13, &v14, &v15, &v16))
+	return
+}
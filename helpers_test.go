@@ -0,0 +1,361 @@
+package vermock_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestAssertTotalCalls(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Put", func(key string, value any) error {
+			return nil
+		}),
+		vermock.ExpectMany[mockCache]("Get", func(key string) (any, bool) {
+			return "bar", true
+		}),
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+	)
+	vermock.AssertTotalCalls(t, cache, 0)
+
+	_ = cache.Put("foo", "bar")
+	cache.Get("foo")
+	cache.Get("foo")
+	cache.Delete("foo")
+
+	if total := vermock.TotalCalls(cache); total != 4 {
+		t.Errorf("expected 4 calls, got %d", total)
+	}
+	vermock.AssertTotalCalls(t, cache, 4)
+
+	mockT := &testing.T{}
+	vermock.AssertTotalCalls(mockT, cache, 3)
+	if !mockT.Failed() {
+		t.Error("expected failure")
+	}
+}
+
+func TestAssertExpectedCalls_multi(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opt    vermock.Option[mockCache]
+		calls  int
+		failed bool
+	}{
+		{name: "many uncalled", opt: vermock.ExpectMany[mockCache]("Delete", func(key string) {}), calls: 0, failed: true},
+		{name: "many called", opt: vermock.ExpectMany[mockCache]("Delete", func(key string) {}), calls: 1},
+		{name: "at most uncalled", opt: vermock.ExpectAtMost[mockCache]("Delete", 1, func(key string) {}), calls: 0},
+		{name: "between below min", opt: vermock.ExpectBetween[mockCache]("Delete", 2, 3, func(key string) {}), calls: 1, failed: true},
+		{name: "between at min", opt: vermock.ExpectBetween[mockCache]("Delete", 2, 3, func(key string) {}), calls: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			cache := vermock.New(mockT, tc.opt)
+			for i := 0; i < tc.calls; i++ {
+				cache.Delete("foo")
+			}
+			vermock.AssertExpectedCalls(mockT, cache)
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
+
+func TestAssertExpectedCalls_withPanicOnFail(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+	)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic")
+		}
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, "failed to make call to Delete") {
+			t.Errorf("unexpected panic: %v", r)
+		}
+		if mockT.Failed() {
+			t.Error("expected no call to t.Error")
+		}
+	}()
+	vermock.AssertExpectedCallsWith(mockT, []vermock.AssertOption{vermock.WithPanicOnFail()}, cache)
+}
+
+func TestCallCountOf(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.ExpectMany[mockCache]("Get", func(key string) (any, bool) {
+			return "bar", true
+		}),
+	)
+	if n := vermock.CallCountOf(cache, "Get"); n != 0 {
+		t.Errorf("expected 0 calls to Get, got %d", n)
+	}
+	cache.Get("foo")
+	cache.Get("foo")
+	if n := vermock.CallCountOf(cache, "Get"); n != 2 {
+		t.Errorf("expected 2 calls to Get, got %d", n)
+	}
+	if n := vermock.CallCountOf(cache, "Put"); n != 0 {
+		t.Errorf("expected 0 calls to unregistered Put, got %d", n)
+	}
+}
+
+func TestHasDelegate(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+		vermock.WithDefault[mockCache]("Get", func(key string) (any, bool) {
+			return nil, false
+		}),
+	)
+	if !vermock.HasDelegate(cache, "Delete") {
+		t.Error("expected delegate for Delete")
+	}
+	cache.Delete("foo")
+	if !vermock.HasDelegate(cache, "Delete") {
+		t.Error("expected delegate for Delete after call")
+	}
+	if !vermock.HasDelegate(cache, "Get") {
+		t.Error("expected default delegate for Get")
+	}
+	if vermock.HasDelegate(cache, "Put") {
+		t.Error("expected no delegate for Put")
+	}
+}
+
+func TestPeek(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+		vermock.ExpectMany[mockCache]("Get", func(key string) (any, bool) {
+			return "bar", true
+		}),
+	)
+	if callable, ok := vermock.Peek(cache, "Delete"); !ok {
+		t.Error("expected next call to Delete")
+	} else if _, ok := callable.(vermock.Value); !ok {
+		t.Errorf("expected Value, got %T", callable)
+	}
+	if n := vermock.CallCountOf(cache, "Delete"); n != 0 {
+		t.Errorf("expected Peek not to consume the call, got %d calls", n)
+	}
+	cache.Delete("foo")
+	if callable, ok := vermock.Peek(cache, "Delete"); ok {
+		t.Errorf("expected no next call to Delete, got %T", callable)
+	}
+	for i := 0; i < 2; i++ {
+		callable, ok := vermock.Peek(cache, "Get")
+		if !ok {
+			t.Fatal("expected next call to Get")
+		}
+		if multi, ok := callable.(vermock.MultiCallable); !ok || !multi.MultiCallable() {
+			t.Errorf("expected MultiCallable, got %T", callable)
+		}
+		cache.Get("foo")
+	}
+	if _, ok := vermock.Peek(cache, "Put"); ok {
+		t.Error("expected no next call to unregistered Put")
+	}
+}
+
+func TestAssertCalled(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.ExpectMany[mockCache]("Delete", func(key string) {}),
+	)
+	vermock.AssertNotCalled(mockT, cache, "Delete")
+	if mockT.Failed() {
+		t.Fatal("expected no failure before call")
+	}
+	vermock.AssertCalled(mockT, cache, "Delete")
+	if !mockT.Failed() {
+		t.Fatal("expected failure before call")
+	}
+
+	mockT = &testing.T{}
+	cache = vermock.New(mockT,
+		vermock.ExpectMany[mockCache]("Delete", func(key string) {}),
+	)
+	cache.Delete("foo")
+	cache.Delete("foo")
+	vermock.AssertCalled(mockT, cache, "Delete")
+	if mockT.Failed() {
+		t.Fatal("expected no failure after call")
+	}
+	vermock.AssertNotCalled(mockT, cache, "Delete")
+	if !mockT.Failed() {
+		t.Fatal("expected failure after call")
+	}
+}
+
+func TestOverride(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.ExpectTimes[mockCache]("Put", 3, func(key string, value any) error {
+			return nil
+		}),
+	)
+	for i := 0; i < 2; i++ {
+		if err := cache.Put("foo", "bar"); err != nil {
+			t.Fatalf("unexpected error before override: %v", err)
+		}
+	}
+
+	failure := errors.New("unavailable")
+	vermock.Override(cache, "Put", func(n vermock.CallCount, key string, value any) error {
+		if n < 2 {
+			t.Errorf("unexpected call count: %d", n)
+		}
+		return failure
+	})
+	for i := 0; i < 3; i++ {
+		if err := cache.Put("foo", "bar"); err != failure {
+			t.Errorf("expected error after override, got %v", err)
+		}
+	}
+
+	// the third expectation was replaced, so it is not required
+	vermock.AssertExpectedCalls(mockT, cache)
+	if mockT.Failed() {
+		t.Error("expected no failure")
+	}
+	if n := vermock.CallCountOf(cache, "Put"); n != 5 {
+		t.Errorf("expected 5 calls to Put, got %d", n)
+	}
+}
+
+func TestResetMethod(t *testing.T) {
+	var got []string
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) {
+			got = append(got, "get 1")
+			return "bar", true
+		}),
+		vermock.Expect[mockCache]("Delete", func(key string) {
+			got = append(got, "delete")
+		}),
+		vermock.Expect[mockCache]("Delete", func(key string) {
+			got = append(got, "delete")
+		}),
+	)
+
+	// phase 1
+	cache.Get("foo")
+	cache.Delete("foo")
+
+	// phase 2
+	vermock.ResetMethod(cache, "Get")
+	if n := vermock.TotalCalls(cache); n != 1 {
+		t.Errorf("expected only the Delete call after reset, got %d", n)
+	}
+	vermock.Expect[mockCache]("Get", func(key string) (any, bool) {
+		got = append(got, "get 2")
+		return nil, false
+	})(cache)
+	cache.Get("foo")
+	cache.Delete("foo")
+
+	vermock.AssertExpectedCalls(t, cache)
+	want := "get 1,delete,get 2,delete"
+	if s := strings.Join(got, ","); s != want {
+		t.Errorf("unexpected calls: expected %q, got %q", want, s)
+	}
+}
+
+func TestReset(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.ExpectInOrder(
+			vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		),
+		vermock.ExpectPrefix[mockCache]("Get", func(key string) (any, bool) { return nil, false }),
+	)
+	_ = cache.Put("foo", "bar")
+
+	vermock.Reset(cache)
+	if n := vermock.TotalCalls(cache); n != 0 {
+		t.Errorf("expected no calls after reset, got %d", n)
+	}
+	vermock.AssertExpectedCalls(t, cache)
+
+	for _, key := range []string{"foo", "bar"} {
+		vermock.Reset(cache)
+		vermock.ExpectInOrder(
+			vermock.Expect[mockCache]("Delete", func(k string) {
+				if k != key {
+					t.Errorf("unexpected key: %q", k)
+				}
+			}),
+		)(cache)
+		cache.Delete(key)
+		vermock.AssertExpectedCalls(t, cache)
+	}
+}
+
+func TestClose_helpers(t *testing.T) {
+	cache := vermock.New[mockCache](t)
+	vermock.Close(cache)
+	vermock.ResetMethod(cache, "Delete")
+	vermock.Reset(cache)
+	vermock.Override(cache, "Delete", func(key string) {})
+	if calls := vermock.SpyCalls(cache, "Delete"); calls != nil {
+		t.Errorf("unexpected spy calls: %v", calls)
+	}
+}
+
+func TestClose(t *testing.T) {
+	const want = `*vermock_test.mockCache "TestClose"`
+	contains := func(active []string) bool {
+		for _, desc := range active {
+			if desc == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	mockT := &cleanupT{}
+	cache := vermock.New(mockT,
+		vermock.WithName[mockCache]("TestClose"),
+		vermock.AutoAssert[mockCache](),
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+	)
+	if active := vermock.ActiveMocks(); !contains(active) {
+		t.Fatalf("expected %s in %q", want, active)
+	}
+
+	vermock.Close(cache)
+	if active := vermock.ActiveMocks(); contains(active) {
+		t.Errorf("expected %s to be removed from %q", want, active)
+	}
+	vermock.Close(cache)
+	mockT.runCleanups()
+	if mockT.Failed() {
+		t.Error("expected no failure after close")
+	}
+}
+
+func TestExpectOnce(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.ExpectOnce[mockCache]("Delete", func(key string) {}),
+		vermock.ExpectMany[mockCache]("Get", func(key string) (any, bool) {
+			return nil, false
+		}),
+	)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic")
+		}
+		want := "failed to make call to Delete: expected one call, got none"
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, want) {
+			t.Errorf("expected %q in panic, got %v", want, r)
+		}
+	}()
+	cache.Get("foo")
+	vermock.AssertExpectedCallsWith(mockT, []vermock.AssertOption{vermock.WithPanicOnFail()}, cache)
+}
@@ -2,6 +2,7 @@ package vermockgen
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"log"
 	"os"
@@ -26,6 +27,28 @@ type GenCmd struct {
 	headerFile     string
 	prefixFileName string
 	tags           string
+	stubTag        string
+	partial        bool
+	typed          bool
+	bounded        bool
+	nolint         string
+	forward        bool
+	xtest          bool
+	dryRun         bool
+	output         string
+	outputSuffix   string
+	generatorCmd   string
+	json           bool
+}
+
+// genReport is the report of the generation of a package printed by -json.
+// OutputPath is empty when nothing was generated for the package, and
+// Warnings is omitted when there are none.
+type genReport struct {
+	PkgPath    string
+	OutputPath string
+	Errors     []string
+	Warnings   []string `json:",omitempty"`
 }
 
 func NewGenCmd(l *log.Logger, f *flag.FlagSet) *GenCmd {
@@ -39,12 +62,32 @@ func (*GenCmd) Synopsis() string {
 	return "generate the vermock_gen.go file for each package"
 }
 func (*GenCmd) Usage() string {
-	return `gen [-header file] [-tags buildtags] [package ...]
+	return `gen [-header file] [-stubtag tag] [-tags buildtags] [-partial] [-typed] [-bounded] [-nolint linters] [-forward] [-xtest] [-n] [-o file] [-output-suffix suffix] [-generator-cmd command] [-json] [package ...]
 
   Given one or more packages, gen creates vermock_gen.go files for each.
 
   If no package is listed, it defaults to ".".
 
+  With -forward, the mock methods call the real implementation assigned to
+  the embedded interface, unless expectations are registered for them.
+
+  With -xtest, the mocks are generated in vermock_gen_test.go files of the
+  external test packages, so that they are excluded from production builds.
+
+  With -n, the generated files are printed to stdout instead of written.
+  With -o, the generated output is written to the given file, or to stdout
+  if the file is -.  With -output-suffix, the generated files are named with
+  the given suffix instead of vermock_gen.go.
+
+  With -generator-cmd, the //go:generate directive of the generated files
+  runs the given command, such as a wrapper of vermockgen, instead of
+  go run -mod=mod github.com/Versent/go-vermock/cmd/vermockgen.
+
+  With -json, a JSON array of the PkgPath, OutputPath and Errors of each
+  package is printed to stdout instead of the log of each package.  With
+  -n, the generated files are then neither written nor printed, and -o -
+  cannot be used.
+
 `
 }
 func (cmd *GenCmd) SetFlags(f *flag.FlagSet) {
@@ -52,10 +95,28 @@ func (cmd *GenCmd) SetFlags(f *flag.FlagSet) {
 		cmd.log = log.Default()
 	}
 	f.StringVar(&cmd.headerFile, "header", "", "path to file to insert as a header in vermock_gen.go")
-	f.StringVar(&cmd.tags, "tags", "", "append build tags to the default vermockstub")
+	f.StringVar(&cmd.stubTag, "stubtag", mock.DefaultStubTag, "build tag of the stub files, which excludes vermock_gen.go")
+	f.StringVar(&cmd.tags, "tags", "", "append build tags to the stub tag")
+	f.BoolVar(&cmd.partial, "partial", false, "generate methods that cannot be forwarded with a body that panics")
+	f.BoolVar(&cmd.typed, "typed", false, "generate ExpectTyped functions that check delegate signatures at compile time")
+	f.BoolVar(&cmd.bounded, "bounded", false, "generate ExpectAtMost functions that bound the number of calls")
+	f.StringVar(&cmd.nolint, "nolint", "", "comma-separated linters, or all, to name in a //nolint directive in vermock_gen.go")
+	f.BoolVar(&cmd.forward, "forward", false, "forward calls without expectations to a real implementation of the embedded interface")
+	f.BoolVar(&cmd.xtest, "xtest", false, "generate vermock_gen_test.go in the external test package")
+	f.BoolVar(&cmd.dryRun, "n", false, "print vermock_gen.go to stdout instead of writing it")
+	f.BoolVar(&cmd.dryRun, "dry-run", false, "same as -n")
+	f.StringVar(&cmd.outputSuffix, "output-suffix", mock.DefaultOutputSuffix, "suffix of the generated file names, before _test for test packages")
+	f.StringVar(&cmd.generatorCmd, "generator-cmd", "", "command to run in the //go:generate directive of vermock_gen.go")
+	f.BoolVar(&cmd.json, "json", false, "print a JSON report of the generated packages to stdout")
+	f.StringVar(&cmd.output, "o", "", "write the generated output to file, or to stdout if file is -, instead of vermock_gen.go")
 }
 
 func (cmd *GenCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...any) subcommands.ExitStatus {
+	if cmd.json && cmd.output == "-" {
+		// The generated output would be interleaved with the JSON report.
+		cmd.log.Println("-json cannot be used with -o -, which also prints to stdout")
+		return subcommands.ExitFailure
+	}
 	var opts mock.GenerateOptions
 	err := mock.WithArgs(
 		mock.WithEnv(os.Environ()),
@@ -63,7 +124,17 @@ func (cmd *GenCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...any) su
 		mock.WithArgs(args...),
 		mock.WithWDFallback(),
 		mock.WithPrefixFileName(cmd.prefixFileName),
+		mock.WithOutputSuffix(cmd.outputSuffix),
+		mock.WithGeneratorCmd(cmd.generatorCmd),
+		mock.WithStubTag(cmd.stubTag),
 		mock.WithTags(cmd.tags),
+		mock.WithPartial(cmd.partial),
+		mock.WithTyped(cmd.typed),
+		mock.WithBounded(cmd.bounded),
+		mock.WithNoLint(cmd.nolint),
+		mock.WithForward(cmd.forward),
+		mock.WithExternalTest(cmd.xtest),
+		cmd.outputOption(),
 	)(&opts)
 	if err != nil {
 		cmd.log.Println(err)
@@ -76,13 +147,16 @@ func (cmd *GenCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...any) su
 		cmd.log.Println("generate failed")
 		return subcommands.ExitFailure
 	}
+	if cmd.json {
+		return cmd.report(outs)
+	}
 	if len(outs) == 0 {
 		return subcommands.ExitSuccess
 	}
 	success := true
 	for _, out := range outs {
-		if len(out.Errs) > 0 {
-			logErrors(cmd.log, out.Errs...)
+		logErrors(cmd.log, out.Errs...)
+		if out.Failed() {
 			cmd.log.Printf("%s: generate failed\n", out.PkgPath)
 			success = false
 		}
@@ -90,7 +164,16 @@ func (cmd *GenCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...any) su
 			// No output. Maybe errors, maybe no directives.
 			continue
 		}
-		if err := out.Commit(); err == nil {
+		if cmd.dryRun {
+			if _, err := os.Stdout.Write(out.Content); err != nil {
+				cmd.log.Printf("%s: failed to print %s: %v\n", out.PkgPath, out.OutputPath, err)
+				success = false
+			}
+			continue
+		}
+		if err := out.Commit(); err == nil && cmd.output == "-" {
+			cmd.log.Printf("%s: wrote stdout\n", out.PkgPath)
+		} else if err == nil {
 			cmd.log.Printf("%s: wrote %s\n", out.PkgPath, out.OutputPath)
 		} else {
 			cmd.log.Printf("%s: failed to write %s: %v\n", out.PkgPath, out.OutputPath, err)
@@ -103,3 +186,51 @@ func (cmd *GenCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...any) su
 	}
 	return subcommands.ExitSuccess
 }
+
+// report commits the generated output, unless -n is given, and prints the
+// JSON report of each package to stdout.
+func (cmd *GenCmd) report(outs []mock.GenerateResult) subcommands.ExitStatus {
+	success := true
+	reports := make([]genReport, 0, len(outs))
+	for _, out := range outs {
+		report := genReport{PkgPath: out.PkgPath, Errors: []string{}}
+		for _, err := range out.Errs {
+			if warning, ok := err.(mock.Warning); ok {
+				report.Warnings = append(report.Warnings, warning.Err.Error())
+				continue
+			}
+			report.Errors = append(report.Errors, err.Error())
+		}
+		if len(out.Content) > 0 {
+			report.OutputPath = out.OutputPath
+			if !cmd.dryRun {
+				if err := out.Commit(); err != nil {
+					report.Errors = append(report.Errors, err.Error())
+				}
+			}
+		}
+		if len(report.Errors) > 0 {
+			success = false
+		}
+		reports = append(reports, report)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(reports); err != nil {
+		cmd.log.Printf("failed to print report: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if !success {
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// outputOption returns the option that directs the generated output to the
+// file named by the -o flag, or to stdout if the file is -.
+func (cmd *GenCmd) outputOption() mock.GenerateOption {
+	if cmd.output == "-" {
+		return mock.WithWriter(os.Stdout)
+	}
+	return mock.WithOutputPath(cmd.output)
+}
@@ -0,0 +1,77 @@
+package vermockgen
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/google/subcommands"
+
+	"github.com/Versent/go-vermock/internal/mock"
+)
+
+type ListCmd struct {
+	log     *log.Logger
+	tags    string
+	stubTag string
+}
+
+func (*ListCmd) Name() string { return "list" }
+func (*ListCmd) Synopsis() string {
+	return "list the mock structs that gen would generate for each package"
+}
+func (*ListCmd) Usage() string {
+	return `list [-stubtag tag] [-tags buildtags] [package ...]
+
+  Given one or more packages, list prints each struct in a stub file that
+  embeds an interface, and each interface marked with a //vermock:mock
+  directive, followed by the interfaces that its mock implements, and the
+  methods that a //vermock:methods directive limits it to.  Nothing is
+  generated.
+
+  If no package is listed, it defaults to ".".
+
+`
+}
+func (cmd *ListCmd) SetFlags(f *flag.FlagSet) {
+	if cmd.log == nil {
+		cmd.log = log.Default()
+	}
+	f.StringVar(&cmd.stubTag, "stubtag", mock.DefaultStubTag, "build tag of the stub files")
+	f.StringVar(&cmd.tags, "tags", "", "append build tags to the stub tag")
+}
+
+func (cmd *ListCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...any) subcommands.ExitStatus {
+	var opts mock.GenerateOptions
+	err := mock.WithArgs(
+		mock.WithEnv(os.Environ()),
+		mock.WithArgs(args...),
+		mock.WithWDFallback(),
+		mock.WithStubTag(cmd.stubTag),
+		mock.WithTags(cmd.tags),
+	)(&opts)
+	if err != nil {
+		cmd.log.Println(err)
+		return subcommands.ExitFailure
+	}
+
+	mockables, errs := mock.List(ctx, packages(f), opts)
+	logErrors(cmd.log, errs...)
+	for _, err := range errs {
+		if _, ok := err.(mock.Warning); !ok {
+			cmd.log.Println("list failed")
+			return subcommands.ExitFailure
+		}
+	}
+	for _, mockable := range mockables {
+		line := fmt.Sprintf("%s.%s: %s", mockable.PkgPath, mockable.Name, strings.Join(mockable.Interfaces, ", "))
+		if mockable.Methods != nil {
+			line += fmt.Sprintf(" (only %s)", strings.Join(mockable.Methods, ", "))
+		}
+		fmt.Println(line)
+	}
+	return subcommands.ExitSuccess
+}
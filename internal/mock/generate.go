@@ -6,14 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/build/constraint"
 	"go/format"
 	"go/token"
 	"go/types"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -30,15 +34,35 @@ type GenerateResult struct {
 	// Content is the gofmt'd source code that was generated. May be nil if
 	// there were errors during generation.
 	Content []byte
-	// Errs is a slice of errors identified during generation.
+	// Errs is a slice of errors identified during generation.  Errors
+	// wrapped in Warning, such as those listing the package, do not prevent
+	// the Content from being generated.
 	Errs []error
+
+	// writer, if not nil, is written to by Commit instead of OutputPath.
+	writer io.Writer
+}
+
+// Failed reports whether any of the Errs is not a Warning.
+func (gen GenerateResult) Failed() bool {
+	for _, err := range gen.Errs {
+		if _, ok := err.(Warning); !ok {
+			return true
+		}
+	}
+	return false
 }
 
-// Commit writes the generated file to disk.
+// Commit writes the generated file to disk, or to the Writer of the
+// GenerateOptions if one was given.
 func (gen GenerateResult) Commit() error {
 	if len(gen.Content) == 0 {
 		return nil
 	}
+	if gen.writer != nil {
+		_, err := gen.writer.Write(gen.Content)
+		return err
+	}
 	return os.WriteFile(gen.OutputPath, gen.Content, 0666)
 }
 
@@ -48,12 +72,74 @@ type GenerateOptions struct {
 	Header []byte
 
 	// PrefixOutputFile is the prefix of the file name to write the generated
-	// output to. The suffix will be "vermock_gen.go" or "vermock_gen_test.go".
+	// output to. The suffix will be OutputSuffix, "vermock_gen.go" or
+	// "vermock_gen_test.go" by default.
 	PrefixOutputFile string
 
+	// OutputSuffix is the suffix of the file name to write the generated
+	// output to, which must end with ".go".  For a test package, "_test" is
+	// inserted before the ".go".  If OutputSuffix is empty,
+	// DefaultOutputSuffix is used.
+	OutputSuffix string
+
+	// GeneratorCmd is the command named by the //go:generate directive of
+	// each generated file, such as a wrapper of vermockgen, which is run
+	// with the same arguments as vermockgen.  If GeneratorCmd is empty,
+	// DefaultGeneratorCmd is used.
+	GeneratorCmd string
+
+	// OutputPath, if not empty, is the path of the file to write the
+	// generated output to, instead of a path derived from the directory of
+	// the package.  A relative path is relative to Dir.  Generate returns an
+	// error if more than one package would be written to OutputPath.
+	OutputPath string
+
+	// Writer, if not nil, is written to by GenerateResult.Commit instead of
+	// the file at the OutputPath of the result.  Like OutputPath, it may
+	// only be written to by one package.
+	Writer io.Writer
+
 	// Tags is a list of additional build tags to add to the generated file.
 	Tags string
 
+	// StubTag is the build tag of the stub files that declare the mock
+	// structs.  The generated file is excluded from builds with the tag.  If
+	// StubTag is empty, DefaultStubTag is used.
+	StubTag string
+
+	// Partial permits the generation of mock methods that cannot forward to
+	// vermock.  The body of such a method panics with a TODO message instead.
+	Partial bool
+
+	// Typed enables the generation of ExpectTyped functions, which only
+	// compile when given a delegate with the signature of the method.
+	Typed bool
+
+	// Bounded enables the generation of ExpectAtMost functions, which bound
+	// the number of calls of a method.
+	Bounded bool
+
+	// NoLint is a comma-separated list of linters, or "all", to be named by
+	// a //nolint directive in each generated file.  If NoLint is empty, no
+	// directive is generated.
+	NoLint string
+
+	// Forward keeps the interfaces embedded in the mock structs, so that a
+	// real implementation may be assigned to them, and makes each mock
+	// method call the real implementation when it is not nil and no
+	// expectations were registered for the method.
+	Forward bool
+
+	// ExternalTest places the mocks of a package in its external test
+	// package, that is, in a vermock_gen_test.go file of package <pkg>_test
+	// that imports the package, so that the mocks are not part of the
+	// package's own build.
+	ExternalTest bool
+
+	// Concurrency is the greatest number of packages to generate at once.
+	// If Concurrency is not positive, GOMAXPROCS is used.
+	Concurrency int
+
 	// Dir is the directory to run the build system's query tool
 	// that provides information about the packages.
 	// If Dir is empty, the tool is run in the current directory.
@@ -104,6 +190,63 @@ func WithPrefixFileName(prefix string) GenerateOption {
 	}
 }
 
+// DefaultOutputSuffix is the suffix of the file name of the generated output
+// when GenerateOptions.OutputSuffix is empty.
+const DefaultOutputSuffix = "vermock_gen.go"
+
+// WithOutputSuffix sets the suffix of the file name to write the generated
+// output to.  It returns an error if the suffix does not end with ".go".
+func WithOutputSuffix(suffix string) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		if suffix != "" && (!strings.HasSuffix(suffix, ".go") || strings.ContainsRune(suffix, filepath.Separator)) {
+			return fmt.Errorf("invalid output suffix %q: expected a file name ending with .go", suffix)
+		}
+		opts.OutputSuffix = suffix
+		return nil
+	}
+}
+
+// DefaultGeneratorCmd is the command named by the //go:generate directive of
+// the generated files when GenerateOptions.GeneratorCmd is empty.
+const DefaultGeneratorCmd = "go run -mod=mod github.com/Versent/go-vermock/cmd/vermockgen"
+
+// WithGeneratorCmd sets the command named by the //go:generate directive of
+// the generated files.
+func WithGeneratorCmd(cmd string) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.GeneratorCmd = strings.TrimSpace(cmd)
+		return nil
+	}
+}
+
+// WithOutputPath sets the path of the file to write the generated output to.
+func WithOutputPath(path string) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.OutputPath = path
+		return nil
+	}
+}
+
+// WithWriter sets the writer to write the generated output to.
+func WithWriter(w io.Writer) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.Writer = w
+		return nil
+	}
+}
+
+// DefaultStubTag is the build tag of stub files when GenerateOptions.StubTag
+// is empty.
+const DefaultStubTag = "vermockstub"
+
+// WithStubTag sets the build tag of the stub files.
+func WithStubTag(tag string) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.StubTag = tag
+		return nil
+	}
+}
+
 // WithTags sets the build tags to use when generating the mock files.
 func WithTags(tags string) GenerateOption {
 	return func(opts *GenerateOptions) error {
@@ -112,6 +255,66 @@ func WithTags(tags string) GenerateOption {
 	}
 }
 
+// WithPartial sets whether mock methods that cannot forward to vermock are
+// generated with a body that panics.
+func WithPartial(partial bool) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.Partial = partial
+		return nil
+	}
+}
+
+// WithTyped sets whether ExpectTyped functions are generated.
+func WithTyped(typed bool) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.Typed = typed
+		return nil
+	}
+}
+
+// WithBounded sets whether ExpectAtMost functions are generated.
+func WithBounded(bounded bool) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.Bounded = bounded
+		return nil
+	}
+}
+
+// WithForward sets whether mock methods forward to a real implementation of
+// the interfaces embedded in the mock structs.
+func WithForward(forward bool) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.Forward = forward
+		return nil
+	}
+}
+
+// WithExternalTest sets whether the mocks of a package are placed in its
+// external test package.
+func WithExternalTest(external bool) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.ExternalTest = external
+		return nil
+	}
+}
+
+// WithNoLint sets the linters to be named by a //nolint directive in each
+// generated file.
+func WithNoLint(linters string) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.NoLint = linters
+		return nil
+	}
+}
+
+// WithConcurrency sets the greatest number of packages to generate at once.
+func WithConcurrency(n int) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		opts.Concurrency = n
+		return nil
+	}
+}
+
 // WithHeader sets the header to insert at the start of each generated file.
 func WithHeader(header []byte) GenerateOption {
 	return func(opts *GenerateOptions) error {
@@ -146,6 +349,28 @@ func WithEnv(env []string) GenerateOption {
 	}
 }
 
+// WithEnvMap sets the environment to use when invoking the build system's
+// query tool to the current environment, that set by WithEnv or otherwise
+// that of the process, overridden by the given variables.
+func WithEnvMap(env map[string]string) GenerateOption {
+	return func(opts *GenerateOptions) error {
+		if opts.Env == nil {
+			opts.Env = os.Environ()
+		}
+		keys := make([]string, 0, len(env))
+		for key := range env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		merged := append([]string(nil), opts.Env...)
+		for _, key := range keys {
+			merged = append(merged, key+"="+env[key])
+		}
+		opts.Env = merged
+		return nil
+	}
+}
+
 // WithArgs applies each GenerateOption in the given slice.  If any of the
 // GenerateOptions return an error, WithArgs will return the error immediately.
 // The args use the any type to be compatible with the subcommands package.
@@ -170,65 +395,147 @@ func WithArgs(args ...any) GenerateOption {
 
 // Generate generates a code file for each package matching the given patterns.
 // The code file will contain mock implementations for each struct type in any
-// file in the package that has the vermockstub build tag.  As a consequence, the
-// generated files will not be included in the package's build when using the
-// vermockstub build tag.  An implementation for each method of each interface
-// type that the struct type embeds will be generated, unless an implementation
-// already exists elsewhere in the package.
-// The generated files will be named vermock_gen.go, with an optional prefix.
+// file in the package that has the stub build tag, vermockstub unless StubTag
+// is given.  As a consequence, the generated files will not be included in the
+// package's build when using the stub build tag.  An implementation for each
+// method of each interface type that the struct type embeds will be generated,
+// unless an implementation already exists elsewhere in the package.  A mock
+// struct will also be generated for each interface type marked with a
+// //vermock:mock directive.  Each mock struct is asserted to implement its
+// interfaces at compile time, unless a //vermock:methods directive on the
+// struct type limits the methods mocked to those listed, and the others are
+// not forwarded.
+// The generated files will be named vermock_gen.go, with an optional prefix,
+// unless an OutputPath or Writer is given, or another OutputSuffix replaces
+// vermock_gen.go.  The output of more than one package to an OutputPath or
+// Writer is an error, as is a prefix and suffix that would name the files
+// with a leading "_" or ".", which the go tool ignores.  With ExternalTest,
+// the generated files will instead be named vermock_gen_test.go and belong to
+// the external test package, which imports the package to reference its
+// types.
 // The generated files will also include a go:generate comment that can be used
-// to regenerate the file.
+// to regenerate the file, with GeneratorCmd or DefaultGeneratorCmd.  The
+// packages are generated concurrently, and the results are sorted by PkgPath.
 func Generate(ctx context.Context, patterns []string, opts GenerateOptions) ([]GenerateResult, []error) {
-	tags := "-tags=vermockstub"
+	if opts.StubTag == "" {
+		opts.StubTag = DefaultStubTag
+	}
+	if opts.OutputSuffix == "" {
+		opts.OutputSuffix = DefaultOutputSuffix
+	}
+	if opts.GeneratorCmd == "" {
+		opts.GeneratorCmd = DefaultGeneratorCmd
+	}
+	if name := opts.PrefixOutputFile + opts.OutputSuffix; opts.OutputPath == "" && opts.Writer == nil &&
+		(strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")) {
+		return nil, []error{fmt.Errorf("invalid output file name %q: the go tool ignores files that begin with _ or .", name)}
+	}
+	tags := "-tags=" + opts.StubTag
 	if opts.Tags != "" {
 		tags += " " + opts.Tags
 	}
 
-	pkgs, errs := load(ctx, opts.Dir, opts.Env, []string{tags}, patterns)
+	pkgs, warnings, errs := load(ctx, opts.Dir, opts.Env, []string{tags}, patterns)
 	if len(errs) > 0 {
 		return nil, errs
 	}
 
+	// Each package is generated independently, by a bounded number of
+	// goroutines, into its own element of generated.
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
 	generated := make([]GenerateResult, len(pkgs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
 	for i, pkg := range pkgs {
-		generated[i].PkgPath = pkg.PkgPath
-		outDir, err := detectOutputDir(pkg.GoFiles)
-		if err != nil {
-			generated[i].Errs = append(generated[i].Errs, err)
-			continue
-		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, pkg *packages.Package) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			generated[i] = generatePackage(pkg, opts)
+			generated[i].Errs = append(warnings[pkg], generated[i].Errs...)
+		}(i, pkg)
+	}
+	wg.Wait()
+	sort.SliceStable(generated, func(i, j int) bool {
+		return generated[i].PkgPath < generated[j].PkgPath
+	})
 
-		outputFile := opts.PrefixOutputFile + "vermock_gen"
-		if strings.HasSuffix(pkg.Name, "_test") {
-			outputFile += "_test"
+	// The output of more than one package cannot share one file or stream.
+	if opts.OutputPath != "" || opts.Writer != nil {
+		var pkgPaths []string
+		for _, gen := range generated {
+			if len(gen.Content) > 0 {
+				pkgPaths = append(pkgPaths, gen.PkgPath)
+			}
 		}
-		outputFile += ".go"
-		generated[i].OutputPath = filepath.Join(outDir, outputFile)
-
-		g := newGen(pkg)
-		findFunctions(g, pkg)
-		errs := generateMocks(g, pkg)
-		if len(errs) > 0 {
-			generated[i].Errs = errs
-			continue
+		if len(pkgPaths) > 1 {
+			return nil, []error{fmt.Errorf("cannot write the output of more than one package to one file: %s", strings.Join(pkgPaths, ", "))}
 		}
+	}
 
-		goSrc := g.frame(opts.Tags)
-		if len(opts.Header) > 0 {
-			goSrc = append(opts.Header, goSrc...)
-		}
-		fmtSrc, err := format.Source(goSrc)
-		if err != nil {
-			// This is likely a bug from a poorly generated source file.
-			// Add an error but also the unformatted source.
-			generated[i].Errs = append(generated[i].Errs, err)
-		} else {
-			goSrc = fmtSrc
+	return generated, nil
+}
+
+// generatePackage generates the code file for the given package, as described
+// by Generate.  The StubTag, OutputSuffix and GeneratorCmd of opts must not
+// be empty.
+func generatePackage(pkg *packages.Package, opts GenerateOptions) (generated GenerateResult) {
+	generated.PkgPath = pkg.PkgPath
+	outDir, err := detectOutputDir(pkg.GoFiles)
+	if err != nil {
+		generated.Errs = append(generated.Errs, err)
+		return
+	}
+
+	external := opts.ExternalTest && !strings.HasSuffix(pkg.Name, "_test")
+	outputFile := opts.PrefixOutputFile + strings.TrimSuffix(opts.OutputSuffix, ".go")
+	if external || strings.HasSuffix(pkg.Name, "_test") {
+		outputFile += "_test"
+	}
+	outputFile += ".go"
+	generated.OutputPath = filepath.Join(outDir, outputFile)
+	if opts.OutputPath != "" {
+		generated.OutputPath = opts.OutputPath
+		if !filepath.IsAbs(opts.OutputPath) {
+			generated.OutputPath = filepath.Join(opts.Dir, opts.OutputPath)
 		}
-		generated[i].Content = goSrc
+	}
+	generated.writer = opts.Writer
+
+	g := newGen(pkg)
+	g.stubTag = opts.StubTag
+	g.partial = opts.Partial
+	g.typed = opts.Typed
+	g.bounded = opts.Bounded
+	g.external = external
+	g.forward = opts.Forward
+	g.outputSuffix = opts.OutputSuffix
+	g.generatorCmd = opts.GeneratorCmd
+	findFunctions(g, pkg)
+	if errs := generateMocks(g, pkg); len(errs) > 0 {
+		generated.Errs = errs
+		return
 	}
 
-	return generated, nil
+	goSrc := g.frame(opts.Tags, opts.NoLint)
+	if len(opts.Header) > 0 {
+		// The header is shared by all packages, so it must not be appended to.
+		goSrc = append(append([]byte(nil), opts.Header...), goSrc...)
+	}
+	fmtSrc, err := format.Source(goSrc)
+	if err != nil {
+		// This is likely a bug from a poorly generated source file.
+		// Add an error but also the unformatted source.
+		generated.Errs = append(generated.Errs, err)
+	} else {
+		goSrc = fmtSrc
+	}
+	generated.Content = goSrc
+	return
 }
 
 func detectOutputDir(paths []string) (string, error) {
@@ -244,13 +551,21 @@ func detectOutputDir(paths []string) (string, error) {
 	return dir, nil
 }
 
-func isMockStub(syntax *ast.File) bool {
+// isMockStub reports whether the file has the stub build tag, in either a
+// //go:build line or a legacy // +build line.  The tag must be required, not
+// negated, by the constraint, and must match in full rather than as a prefix
+// of another tag.
+func isMockStub(syntax *ast.File, stubTag string) bool {
 	for _, group := range syntax.Comments {
 		for _, comment := range group.List {
-			if comment.Text == "// +build vermockstub" {
-				return true
+			if !constraint.IsGoBuild(comment.Text) && !constraint.IsPlusBuild(comment.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
 			}
-			if strings.HasPrefix(comment.Text, "//go:build vermockstub") {
+			if hasTag(expr, stubTag) {
 				return true
 			}
 		}
@@ -258,6 +573,20 @@ func isMockStub(syntax *ast.File) bool {
 	return false
 }
 
+// hasTag reports whether the build constraint names the tag outside of a
+// negation.
+func hasTag(expr constraint.Expr, tag string) bool {
+	switch expr := expr.(type) {
+	case *constraint.TagExpr:
+		return expr.Tag == tag
+	case *constraint.AndExpr:
+		return hasTag(expr.X, tag) || hasTag(expr.Y, tag)
+	case *constraint.OrExpr:
+		return hasTag(expr.X, tag) || hasTag(expr.Y, tag)
+	}
+	return false
+}
+
 func findFunctions(g *gen, pkg *packages.Package) {
 	pkgName, _ := g.resolvePackageName("github.com/Versent/go-vermock")
 	for _, syntax := range pkg.Syntax {
@@ -274,7 +603,8 @@ func findFunctions(g *gen, pkg *packages.Package) {
 			if funcDecl.Recv != nil || funcDecl.Body == nil {
 				continue
 			}
-			// search for calls to vermock.Expect or vermock.ExpectMany
+			// search for calls to vermock.Expect, vermock.ExpectMany or
+			// vermock.ExpectAtMost
 			var funcName, structName, methodName string
 			ast.Inspect(funcDecl.Body, func(node ast.Node) (next bool) {
 				next = true
@@ -293,7 +623,9 @@ func findFunctions(g *gen, pkg *packages.Package) {
 					if sel, ok = index.X.(*ast.SelectorExpr); !ok {
 						return
 					}
-					if sel.Sel.Name != "Expect" && sel.Sel.Name != "ExpectMany" {
+					switch sel.Sel.Name {
+					case "Expect", "ExpectMany", "ExpectAtMost":
+					default:
 						return
 					}
 					if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != pkgName {
@@ -329,7 +661,8 @@ func findFunctions(g *gen, pkg *packages.Package) {
 
 func generateMocks(g *gen, pkg *packages.Package) (errs []error) {
 	for _, syntax := range pkg.Syntax {
-		if !isMockStub(syntax) {
+		errs = append(errs, generateDirectiveMocks(g, pkg, syntax)...)
+		if !isMockStub(syntax, g.stubTag) {
 			continue
 		}
 
@@ -376,9 +709,10 @@ func generateMocks(g *gen, pkg *packages.Package) (errs []error) {
 					Tok: token.TYPE,
 					Specs: []ast.Spec{
 						&ast.TypeSpec{
-							Doc:     clone(typeSpec.Doc),
-							Comment: clone(typeSpec.Comment),
-							Name:    clone(typeSpec.Name),
+							Doc:        clone(typeSpec.Doc),
+							Comment:    clone(typeSpec.Comment),
+							Name:       clone(typeSpec.Name),
+							TypeParams: clone(typeSpec.TypeParams),
 							Type: &ast.StructType{
 								Fields: mockFields,
 							},
@@ -386,35 +720,86 @@ func generateMocks(g *gen, pkg *packages.Package) (errs []error) {
 					},
 				}
 
-				mockSize := pkg.TypesSizes.Sizeof(structType)
+				if typeSpec.TypeParams != nil {
+					g.typeParams[typeSpec.Name.Name] = typeSpec.TypeParams
+				}
+
+				only, err := directiveMethods(typeDoc(genDecl, typeSpec))
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", pkg.Fset.Position(typeSpec.Pos()), err))
+					continue
+				}
+				if only != nil {
+					g.only[typeSpec.Name.Name] = only
+				}
+
+				// The mock struct is zero-sized when all of the fields
+				// that it keeps are, which cannot be derived from the size
+				// of the stub struct, as that includes padding.
+				zeroSize := true
+				mocked := false
 
 				// Check for embedded interfaces and generate mock methods
 				for i := 0; i < structType.NumFields(); i++ {
 					field := structType.Field(i)
-					if field.Embedded() {
+					if field.Embedded() && typeSpec.TypeParams == nil && (only == nil || g.forward) {
 						// Generate:
 						//   var _ <ifaceType> = (*<typeSpec.Name>)(nil)
+						// which cannot be declared for a generic struct, as
+						// its type parameters are not in scope, nor when only
+						// some methods are mocked, unless the others are
+						// forwarded.
 						err := g.addInterfaceAssertion(
-							*clone(&typeSpec.Type.(*ast.StructType).Fields.List[i].Type),
+							g.fieldType(typeSpec.Type.(*ast.StructType).Fields.List[i].Type, field.Type()),
 							clone(typeSpec.Name),
 						)
 						if err != nil {
 							errs = append(errs, err)
 						}
+					}
+					if field.Embedded() {
 
 						ifaceType, ok := field.Type().Underlying().(*types.Interface)
-						if ok {
-							mockSize -= pkg.TypesSizes.Sizeof(field.Type())
-							if err := generateMockMethods(g, ifaceType, typeSpec.Name.Name); err != nil {
+						if ok && g.forward {
+							// Keep the interface, to hold the real implementation
+							if err := generateMockMethods(g, ifaceType, typeSpec.Name.Name, field.Name()); err != nil {
 								errs = append(errs, err)
 							}
+							mocked = true
+						} else if ok {
+							if err := generateMockMethods(g, ifaceType, typeSpec.Name.Name, ""); err != nil {
+								errs = append(errs, err)
+							}
+							mocked = true
 							continue
 						}
 					}
-					mockFields.List = append(mockFields.List, clone(typeSpec.Type.(*ast.StructType).Fields.List[i]))
+					if pkg.TypesSizes.Sizeof(field.Type()) > 0 {
+						zeroSize = false
+					}
+					mockField := clone(typeSpec.Type.(*ast.StructType).Fields.List[i])
+					mockField.Type = g.fieldType(mockField.Type, field.Type())
+					mockFields.List = append(mockFields.List, mockField)
 				}
 
-				if mockSize == 0 {
+				var missing []string
+				for methodName := range only {
+					if g.methods[typeSpec.Name.Name][methodName] == nil {
+						missing = append(missing, methodName)
+					}
+				}
+				sort.Strings(missing)
+				for _, methodName := range missing {
+					errs = append(errs, fmt.Errorf("%s: %s directive lists method %s, which %s does not embed", pkg.Fset.Position(typeSpec.Pos()), methodsDirective, methodName, typeSpec.Name))
+				}
+
+				if mocked {
+					if err := addStringMethod(g, typeSpec.Name.Name); err != nil {
+						errs = append(errs, err)
+					}
+				}
+
+				if zeroSize {
 					mockFields.List = append(mockFields.List, &ast.Field{
 						Names: []*ast.Ident{{Name: "_"}},
 						Type:  ast.NewIdent("byte"),
@@ -427,7 +812,7 @@ func generateMocks(g *gen, pkg *packages.Package) (errs []error) {
 				}
 
 				// Add the mock struct to the file
-				err := g.addDecl(typeSpec.Name, mockDecl)
+				err = g.addDecl(typeSpec.Name, mockDecl)
 				if err != nil {
 					errs = append(errs, err)
 				}
@@ -444,20 +829,195 @@ func generateMocks(g *gen, pkg *packages.Package) (errs []error) {
 	return errs
 }
 
-func generateMockMethods(g *gen, iface *types.Interface, structName string) error {
+// mockDirective is the directive that marks an interface type to be mocked
+// without a stub.
+const mockDirective = "//vermock:mock"
+
+// typeDoc returns the doc comment of the type declared by the given spec of
+// genDecl, which is that of genDecl when the type is declared on its own.
+func typeDoc(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) *ast.CommentGroup {
+	if typeSpec.Doc == nil && len(genDecl.Specs) == 1 {
+		return genDecl.Doc
+	}
+	return typeSpec.Doc
+}
+
+// methodsDirective is the directive that limits the methods mocked for a stub
+// struct to those listed, separated by commas.
+const methodsDirective = "//vermock:methods"
+
+// directiveMethods returns the set of methods listed by the //vermock:methods
+// directive in doc, or nil if there is none.
+func directiveMethods(doc *ast.CommentGroup) (map[string]bool, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	for _, comment := range doc.List {
+		rest, ok := strings.CutPrefix(comment.Text, methodsDirective)
+		if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		only := make(map[string]bool)
+		for _, name := range strings.Split(rest, ",") {
+			name = strings.TrimSpace(name)
+			if !token.IsIdentifier(name) {
+				return nil, fmt.Errorf("%s directive lists invalid method name %q", methodsDirective, name)
+			}
+			only[name] = true
+		}
+		return only, nil
+	}
+	return nil, nil
+}
+
+// generateDirectiveMocks generates a mock struct for each interface type in the
+// given file that is marked with the //vermock:mock directive, as though it
+// were embedded in a struct in a stub file.  The name of the mock struct
+// follows the directive, and defaults to the name of the interface prefixed
+// with mock.
+func generateDirectiveMocks(g *gen, pkg *packages.Package, syntax *ast.File) (errs []error) {
+	for _, decl := range syntax.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structName, ok := directiveStructName(typeDoc(genDecl, typeSpec), typeSpec.Name.Name)
+			if !ok {
+				continue
+			}
+			pos := pkg.Fset.Position(typeSpec.Pos())
+			iface, ok := pkg.TypesInfo.ObjectOf(typeSpec.Name).Type().Underlying().(*types.Interface)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: %s directive on non-interface type %s", pos, mockDirective, typeSpec.Name))
+				continue
+			}
+			if typeSpec.TypeParams != nil {
+				errs = append(errs, fmt.Errorf("%s: %s directive on generic interface %s", pos, mockDirective, typeSpec.Name))
+				continue
+			}
+
+			// Generate:
+			//   var _ <typeSpec.Name> = (*<structName>)(nil)
+			ifaceType := g.fieldType(ast.NewIdent(typeSpec.Name.Name), pkg.TypesInfo.ObjectOf(typeSpec.Name).Type())
+			if err := g.addInterfaceAssertion(ifaceType, ast.NewIdent(structName)); err != nil {
+				errs = append(errs, err)
+			}
+			if err := generateMockMethods(g, iface, structName, ""); err != nil {
+				errs = append(errs, err)
+			}
+			if err := addStringMethod(g, structName); err != nil {
+				errs = append(errs, err)
+			}
+			mockDecl := &ast.GenDecl{
+				Tok: token.TYPE,
+				Specs: []ast.Spec{
+					&ast.TypeSpec{
+						Name: ast.NewIdent(structName),
+						Type: &ast.StructType{
+							Fields: &ast.FieldList{
+								List: []*ast.Field{{
+									Names: []*ast.Ident{{Name: "_"}},
+									Type:  ast.NewIdent("byte"),
+									Comment: &ast.CommentGroup{
+										List: []*ast.Comment{{
+											Text: "// prevent zero-size struct",
+										}},
+									},
+								}},
+							},
+						},
+					},
+				},
+			}
+			if err := g.addDecl(ast.NewIdent(structName), mockDecl); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// directiveStructName returns the name of the mock struct given by the
+// //vermock:mock directive in doc, if any, for the interface with the given
+// name.
+func directiveStructName(doc *ast.CommentGroup, ifaceName string) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, comment := range doc.List {
+		rest, ok := strings.CutPrefix(comment.Text, mockDirective)
+		if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		if fields := strings.Fields(rest); len(fields) > 0 {
+			return fields[0], true
+		}
+		return "mock" + ifaceName, true
+	}
+	return "", false
+}
+
+// generateMockMethods generates the mock methods and Expect functions for each
+// method of the given interface, including those of the interfaces that it
+// embeds.  A method that is shared with another interface embedded in the
+// same struct is only generated once, as the methods already generated for
+// each struct are recorded in g.methods, but a method with the same name and
+// a different signature is an error, as one mock method cannot implement
+// both.  If forwardTo is not empty, it is the name of the field of the struct
+// that holds a real implementation of the interface, which the mock methods
+// forward to.  When the methods of the struct are limited by a
+// //vermock:methods directive, as recorded in g.only, the other methods are
+// skipped.
+func generateMockMethods(g *gen, iface *types.Interface, structName, forwardTo string) error {
+	generated := g.methods[structName]
+	if generated == nil {
+		generated = make(map[string]*types.Signature)
+		g.methods[structName] = generated
+	}
+
 	// Iterate through each method in the interface
 	for i := 0; i < iface.NumMethods(); i++ {
 		method := iface.Method(i)
 		methodName := method.Name()
 		sig := method.Type().(*types.Signature)
 
+		if prev := generated[methodName]; prev != nil {
+			if !types.Identical(prev, sig) {
+				return fmt.Errorf("%s.%s: embedded interfaces have conflicting methods %s and %s: embed them in separate structs", structName, methodName, g.typeString(prev), g.typeString(sig))
+			}
+			continue
+		}
+		if only := g.only[structName]; only != nil && !only[methodName] {
+			continue
+		}
+		generated[methodName] = sig
+
+		if !method.Exported() && method.Pkg() != g.localPkg() {
+			return fmt.Errorf("%s.%s: cannot implement method unexported from package %s", structName, methodName, method.Pkg().Path())
+		}
+		if obj := unexportedTypeName(g.localPkg(), sig); obj != nil {
+			return fmt.Errorf("%s.%s: cannot reference type %s unexported from package %s", structName, methodName, obj.Name(), obj.Pkg().Path())
+		}
+
 		if err := addExpectFunc(g, "Expect", structName, methodName, sig); err != nil {
 			return err
 		}
 		if err := addExpectFunc(g, "ExpectMany", structName, methodName, sig); err != nil {
 			return err
 		}
-		if err := addMockMethod(g, structName, methodName, sig); err != nil {
+		if g.bounded {
+			if err := addExpectFunc(g, "ExpectAtMost", structName, methodName, sig); err != nil {
+				return err
+			}
+		}
+		if g.typed {
+			if err := addExpectTypedFunc(g, structName, methodName, sig); err != nil {
+				return err
+			}
+		}
+		if err := addMockMethod(g, structName, methodName, forwardTo, sig); err != nil {
 			return err
 		}
 	}
@@ -465,7 +1025,57 @@ func generateMockMethods(g *gen, iface *types.Interface, structName string) erro
 	return nil
 }
 
-func addMockMethod(g *gen, structName, methodName string, sig *types.Signature) (err error) {
+// unexportedTypeName returns the first named type referenced by typ that is
+// not exported from a package other than pkg, or nil if there is none.
+func unexportedTypeName(pkg *types.Package, typ types.Type) *types.TypeName {
+	switch typ := typ.(type) {
+	case *types.Named:
+		if obj := typ.Obj(); !obj.Exported() && obj.Pkg() != nil && obj.Pkg() != pkg {
+			return obj
+		}
+		if args := typ.TypeArgs(); args != nil {
+			for i := 0; i < args.Len(); i++ {
+				if obj := unexportedTypeName(pkg, args.At(i)); obj != nil {
+					return obj
+				}
+			}
+		}
+	case *types.Pointer:
+		return unexportedTypeName(pkg, typ.Elem())
+	case *types.Slice:
+		return unexportedTypeName(pkg, typ.Elem())
+	case *types.Array:
+		return unexportedTypeName(pkg, typ.Elem())
+	case *types.Chan:
+		return unexportedTypeName(pkg, typ.Elem())
+	case *types.Map:
+		if obj := unexportedTypeName(pkg, typ.Key()); obj != nil {
+			return obj
+		}
+		return unexportedTypeName(pkg, typ.Elem())
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{typ.Params(), typ.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if obj := unexportedTypeName(pkg, tuple.At(i).Type()); obj != nil {
+					return obj
+				}
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			if obj := unexportedTypeName(pkg, typ.Field(i).Type()); obj != nil {
+				return obj
+			}
+		}
+	}
+	return nil
+}
+
+// maxResults is the greatest number of results that a mock method can forward
+// with one of the vermock.CallN functions.
+const maxResults = 16
+
+func addMockMethod(g *gen, structName, methodName, forwardTo string, sig *types.Signature) (err error) {
 	// Start building the function declaration
 	methDecl := &ast.FuncDecl{
 		Recv: &ast.FieldList{
@@ -473,7 +1083,7 @@ func addMockMethod(g *gen, structName, methodName string, sig *types.Signature)
 				{
 					Names: []*ast.Ident{{Name: "m"}},
 					Type: &ast.StarExpr{
-						X: ast.NewIdent(structName),
+						X: g.structType(structName),
 					},
 				},
 			},
@@ -487,11 +1097,35 @@ func addMockMethod(g *gen, structName, methodName string, sig *types.Signature)
 		return
 	}
 
-	methDecl.Type.Params = fieldList("v", sig.Variadic(), sig.Params())
-	methDecl.Type.Results = fieldList("", false, sig.Results())
+	// The results are listed first, so that the packages referenced by the
+	// result types in the body are imported before the parameters are named.
+	methDecl.Type.Results = fieldList(g, "", false, sig.Results())
+	methDecl.Type.Params = fieldList(g, "v", sig.Variadic(), sig.Params())
+
+	if n := sig.Results().Len(); n > maxResults {
+		if !g.partial {
+			return fmt.Errorf("%s.%s: unable to forward %d results, at most %d are supported", structName, methodName, n, maxResults)
+		}
+		// Generate a body that panics, so the gap is loud at runtime
+		methDecl.Body = &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ExprStmt{X: &ast.CallExpr{
+				Fun: ast.NewIdent("panic"),
+				Args: []ast.Expr{
+					&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote("vermock: TODO implement " + methodName)},
+				},
+			}},
+		}}
+		if forwardTo != "" {
+			methDecl.Body.List = append([]ast.Stmt{forwardStmt(g, methodName, forwardTo, sig)}, methDecl.Body.List...)
+		}
+		return g.addDecl(methDecl.Name, methDecl)
+	}
 
 	// Create a function body (block statement)
 	methDecl.Body = &ast.BlockStmt{List: []ast.Stmt{}}
+	if forwardTo != "" {
+		methDecl.Body.List = append(methDecl.Body.List, forwardStmt(g, methodName, forwardTo, sig))
+	}
 	call := &ast.CallExpr{
 		Fun: &ast.SelectorExpr{
 			X:   ast.NewIdent(g.resolveImportName("vermock", "github.com/Versent/go-vermock")),
@@ -502,7 +1136,7 @@ func addMockMethod(g *gen, structName, methodName string, sig *types.Signature)
 			&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", methodName)},
 		},
 	}
-	forTuple("v", sig.Params(), func(_ int, name string, _ *types.Var) {
+	g.forMethodTuple("v", sig.Params(), func(_ int, name string, _ *types.Var) {
 		call.Args = append(call.Args, ast.NewIdent(name))
 	})
 	if sig.Results().Len() > 0 {
@@ -527,6 +1161,96 @@ func addMockMethod(g *gen, structName, methodName string, sig *types.Signature)
 	return g.addDecl(methDecl.Name, methDecl)
 }
 
+// forwardStmt returns a statement that calls the method with the given name of
+// the real implementation held by the field forwardTo, when it is not nil and
+// no expectations were registered for the method:
+//
+//	if m.<forwardTo> != nil && !vermock.HasDelegate(m, "<methodName>") {
+//		return m.<forwardTo>.<methodName>(v0, v1...)
+//	}
+func forwardStmt(g *gen, methodName, forwardTo string, sig *types.Signature) ast.Stmt {
+	field := &ast.SelectorExpr{X: ast.NewIdent("m"), Sel: ast.NewIdent(forwardTo)}
+	call := &ast.CallExpr{
+		Fun: &ast.SelectorExpr{X: field, Sel: ast.NewIdent(methodName)},
+	}
+	g.forMethodTuple("v", sig.Params(), func(_ int, name string, _ *types.Var) {
+		call.Args = append(call.Args, ast.NewIdent(name))
+	})
+	if sig.Variadic() {
+		// any valid position prints the ellipsis
+		call.Ellipsis = 1
+	}
+	body := &ast.BlockStmt{}
+	if sig.Results().Len() > 0 {
+		body.List = []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{call}}}
+	} else {
+		body.List = []ast.Stmt{&ast.ExprStmt{X: call}, &ast.ReturnStmt{}}
+	}
+	return &ast.IfStmt{
+		Cond: &ast.BinaryExpr{
+			X:  &ast.BinaryExpr{X: field, Op: token.NEQ, Y: ast.NewIdent("nil")},
+			Op: token.LAND,
+			Y: &ast.UnaryExpr{
+				Op: token.NOT,
+				X: &ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X:   ast.NewIdent(g.resolveImportName("vermock", "github.com/Versent/go-vermock")),
+						Sel: ast.NewIdent("HasDelegate"),
+					},
+					Args: []ast.Expr{
+						ast.NewIdent("m"),
+						&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", methodName)},
+					},
+				},
+			},
+		},
+		Body: body,
+	}
+}
+
+// addStringMethod adds a String method to the mock struct with the given
+// name, which summarises the mock with vermock.Summary, unless the struct
+// already has a String method.
+func addStringMethod(g *gen, structName string) error {
+	methDecl := &ast.FuncDecl{
+		Recv: &ast.FieldList{
+			List: []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: "m"}},
+					Type: &ast.StarExpr{
+						X: g.structType(structName),
+					},
+				},
+			},
+		},
+		Name: ast.NewIdent("String"),
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{},
+			Results: &ast.FieldList{
+				List: []*ast.Field{{Type: ast.NewIdent("string")}},
+			},
+		},
+	}
+
+	if _, ok := g.funcs[g.keyForFunc(methDecl)]; ok {
+		// Method already exists
+		return nil
+	}
+
+	methDecl.Body = &ast.BlockStmt{List: []ast.Stmt{
+		&ast.ReturnStmt{Results: []ast.Expr{
+			&ast.CallExpr{
+				Fun: &ast.SelectorExpr{
+					X:   ast.NewIdent(g.resolveImportName("vermock", "github.com/Versent/go-vermock")),
+					Sel: ast.NewIdent("Summary"),
+				},
+				Args: []ast.Expr{ast.NewIdent("m")},
+			},
+		}},
+	}}
+	return g.addDecl(methDecl.Name, methDecl)
+}
+
 func addExpectFunc(g *gen, funcName, structName, methodName string, sig *types.Signature) error {
 	specName := fmt.Sprintf("%s[%s](%q)", funcName, structName, methodName)
 	if _, ok := g.funcs[specName]; ok {
@@ -534,20 +1258,9 @@ func addExpectFunc(g *gen, funcName, structName, methodName string, sig *types.S
 		return nil
 	}
 
-	// Disambiguate the function name
-	name := ast.NewIdent(funcName + methodName)
-	if _, ok := g.funcs[name.Name]; ok {
-		if token.IsExported(structName) {
-			name = ast.NewIdent(funcName + structName + methodName)
-		} else {
-			name = ast.NewIdent(funcName + cases.Title(language.AmericanEnglish, cases.NoLower).String(structName) + methodName)
-		}
-	}
-	if _, ok := g.funcs[name.Name]; ok {
-		name = ast.NewIdent(name.Name + "T")
-	}
-	if _, ok := g.funcs[name.Name]; ok {
-		return fmt.Errorf("unable to disambiguate function name %q", name.Name)
+	name, err := expectFuncName(g, funcName, structName, methodName)
+	if err != nil {
+		return err
 	}
 
 	delegateType := &ast.FuncType{
@@ -561,7 +1274,7 @@ func addExpectFunc(g *gen, funcName, structName, methodName string, sig *types.S
 			}},
 		},
 	}
-	if funcName == "ExpectMany" {
+	if funcName == "ExpectMany" || funcName == "ExpectAtMost" {
 		delegateType.Params.List = append(delegateType.Params.List, &ast.Field{
 			Names: []*ast.Ident{{Name: "_"}},
 			Type: &ast.SelectorExpr{
@@ -573,13 +1286,14 @@ func addExpectFunc(g *gen, funcName, structName, methodName string, sig *types.S
 	funcDecl := &ast.FuncDecl{
 		Name: name,
 		Type: &ast.FuncType{
+			TypeParams: clone(g.typeParams[structName]),
 			Results: &ast.FieldList{
 				List: []*ast.Field{{
 					Type: &ast.FuncType{
 						Params: &ast.FieldList{
 							List: []*ast.Field{{
 								Type: &ast.StarExpr{
-									X: ast.NewIdent(structName),
+									X: g.structType(structName),
 								},
 							}},
 						},
@@ -601,7 +1315,7 @@ func addExpectFunc(g *gen, funcName, structName, methodName string, sig *types.S
 							X:   ast.NewIdent(g.resolveImportName("vermock", "github.com/Versent/go-vermock")),
 							Sel: ast.NewIdent(funcName),
 						},
-						Indices: []ast.Expr{ast.NewIdent(structName)},
+						Indices: []ast.Expr{g.structType(structName)},
 					},
 					Args: []ast.Expr{
 						&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", methodName)},
@@ -611,15 +1325,177 @@ func addExpectFunc(g *gen, funcName, structName, methodName string, sig *types.S
 			},
 		}},
 	}
+	appendDelegateFields(g, delegateType, sig)
+
+	var doc string
+	switch funcName {
+	case "ExpectMany":
+		doc = "registers an expectation for all remaining calls to"
+	case "ExpectAtMost":
+		doc = "registers an expectation for at most max calls to"
+	default:
+		doc = "registers an expectation for"
+	}
+	funcDecl.Doc = &ast.CommentGroup{
+		List: []*ast.Comment{{
+			Text: fmt.Sprintf("// %s %s %s.%s.", name.Name, doc, structName, methodName),
+		}},
+	}
+
+	if funcName == "ExpectAtMost" {
+		// Generate:
+		//   func ExpectAtMost<methodName>(max int, delegate ...) ...
+		funcDecl.Type.Params.List = append([]*ast.Field{{
+			Names: []*ast.Ident{{Name: "max"}},
+			Type:  ast.NewIdent("int"),
+		}}, funcDecl.Type.Params.List...)
+		call := funcDecl.Body.List[0].(*ast.ReturnStmt).Results[0].(*ast.CallExpr)
+		call.Args = []ast.Expr{call.Args[0], ast.NewIdent("max"), call.Args[1]}
+	}
+
+	g.funcs[specName] = struct{}{}
+
+	// Generate the source code for the function
+	return g.addDecl(funcDecl.Name, funcDecl)
+}
+
+// addExpectTypedFunc generates a function that registers a delegate with
+// vermock.ExpectTyped.  The type of the delegate is constrained to the
+// signature of the method, with or without a leading testing.TB, so that a
+// delegate with the wrong signature fails to compile.
+func addExpectTypedFunc(g *gen, structName, methodName string, sig *types.Signature) error {
+	const funcName = "ExpectTyped"
+	specName := fmt.Sprintf("%s[%s](%q)", funcName, structName, methodName)
+	if _, ok := g.funcs[specName]; ok {
+		// Custom implementation already exists
+		return nil
+	}
+
+	name, err := expectFuncName(g, funcName, structName, methodName)
+	if err != nil {
+		return err
+	}
+
+	methodType := &ast.FuncType{Params: &ast.FieldList{}}
+	appendDelegateFields(g, methodType, sig)
+	methodTBType := &ast.FuncType{
+		Params: &ast.FieldList{
+			List: []*ast.Field{{
+				Names: []*ast.Ident{{Name: "_"}},
+				Type: &ast.SelectorExpr{
+					X:   ast.NewIdent(g.resolveImportName("testing", "testing")),
+					Sel: ast.NewIdent("TB"),
+				},
+			}},
+		},
+	}
+	appendDelegateFields(g, methodTBType, sig)
+
+	funcDecl := &ast.FuncDecl{
+		Name: name,
+		Type: &ast.FuncType{
+			TypeParams: &ast.FieldList{
+				List: []*ast.Field{{
+					Names: []*ast.Ident{{Name: "F"}},
+					Type: &ast.InterfaceType{
+						Methods: &ast.FieldList{
+							List: []*ast.Field{{
+								Type: &ast.BinaryExpr{
+									X:  methodType,
+									Op: token.OR,
+									Y:  methodTBType,
+								},
+							}},
+						},
+					},
+				}},
+			},
+			Results: &ast.FieldList{
+				List: []*ast.Field{{
+					Type: &ast.FuncType{
+						Params: &ast.FieldList{
+							List: []*ast.Field{{
+								Type: &ast.StarExpr{
+									X: g.structType(structName),
+								},
+							}},
+						},
+					},
+				}},
+			},
+			Params: &ast.FieldList{
+				List: []*ast.Field{{
+					Names: []*ast.Ident{{Name: "delegate"}},
+					Type:  ast.NewIdent("F"),
+				}},
+			},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ReturnStmt{
+				Results: []ast.Expr{&ast.CallExpr{
+					Fun: &ast.IndexListExpr{
+						X: &ast.SelectorExpr{
+							X:   ast.NewIdent(g.resolveImportName("vermock", "github.com/Versent/go-vermock")),
+							Sel: ast.NewIdent(funcName),
+						},
+						Indices: []ast.Expr{g.structType(structName)},
+					},
+					Args: []ast.Expr{
+						&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", methodName)},
+						ast.NewIdent("delegate"),
+					},
+				}},
+			},
+		}},
+	}
+
+	if typeParams := g.typeParams[structName]; typeParams != nil {
+		funcDecl.Type.TypeParams.List = append(clone(typeParams).List, funcDecl.Type.TypeParams.List...)
+	}
+	funcDecl.Doc = &ast.CommentGroup{
+		List: []*ast.Comment{{
+			Text: fmt.Sprintf("// %s registers an expectation for %s.%s, with a delegate that is checked at compile time.", name.Name, structName, methodName),
+		}},
+	}
+
+	g.funcs[specName] = struct{}{}
+
+	// Generate the source code for the function
+	return g.addDecl(funcDecl.Name, funcDecl)
+}
+
+// expectFuncName returns a name for an Expect function that does not collide
+// with any existing function.
+func expectFuncName(g *gen, funcName, structName, methodName string) (*ast.Ident, error) {
+	name := ast.NewIdent(funcName + methodName)
+	if _, ok := g.funcs[name.Name]; ok {
+		if token.IsExported(structName) {
+			name = ast.NewIdent(funcName + structName + methodName)
+		} else {
+			name = ast.NewIdent(funcName + cases.Title(language.AmericanEnglish, cases.NoLower).String(structName) + methodName)
+		}
+	}
+	if _, ok := g.funcs[name.Name]; ok {
+		name = ast.NewIdent(name.Name + "T")
+	}
+	if _, ok := g.funcs[name.Name]; ok {
+		return nil, fmt.Errorf("unable to disambiguate function name %q", name.Name)
+	}
+	return name, nil
+}
+
+// appendDelegateFields appends the parameters and results of sig to the given
+// delegate function type.
+func appendDelegateFields(g *gen, delegateType *ast.FuncType, sig *types.Signature) {
 	forTuple("v", sig.Params(), func(_ int, name string, t *types.Var) {
 		delegateType.Params.List = append(delegateType.Params.List, &ast.Field{
 			Names: []*ast.Ident{{Name: name}},
-			Type:  ast.NewIdent(t.Type().String()),
+			Type:  ast.NewIdent(g.typeString(t.Type())),
 		})
 	})
 	forTuple("", sig.Results(), func(_ int, name string, t *types.Var) {
 		field := &ast.Field{
-			Type: ast.NewIdent(t.Type().String()),
+			Type: ast.NewIdent(g.typeString(t.Type())),
 		}
 		if name != "" {
 			field.Names = []*ast.Ident{{Name: name}}
@@ -629,11 +1505,6 @@ func addExpectFunc(g *gen, funcName, structName, methodName string, sig *types.S
 		}
 		delegateType.Results.List = append(delegateType.Results.List, field)
 	})
-
-	g.funcs[specName] = struct{}{}
-
-	// Generate the source code for the function
-	return g.addDecl(funcDecl.Name, funcDecl)
 }
 
 func forTuple(prefix string, tuple *types.Tuple, f func(int, string, *types.Var)) {
@@ -649,20 +1520,48 @@ func forTuple(prefix string, tuple *types.Tuple, f func(int, string, *types.Var)
 	}
 }
 
-// fieldList returns a field list for the given tuple.
-func fieldList(prefix string, variadic bool, tuple *types.Tuple) *ast.FieldList {
+// forMethodTuple is like forTuple, except that the names are those of the
+// parameters or results of a mock method.  A blank parameter is named like
+// an unnamed one, so that it can be passed on, and a name that would shadow
+// the receiver m or an imported package in the body of the method, such as
+// vermock, is suffixed with an underscore.
+func (g *gen) forMethodTuple(prefix string, tuple *types.Tuple, f func(int, string, *types.Var)) {
+	forTuple(prefix, tuple, func(i int, name string, v *types.Var) {
+		if name == "_" && prefix != "" {
+			name = prefix + strconv.Itoa(i)
+		}
+		if name == "m" || g.isImportName(name) {
+			name += "_"
+		}
+		f(i, name, v)
+	})
+}
+
+// isImportName reports whether name is the name of a package imported by the
+// generated source.
+func (g *gen) isImportName(name string) bool {
+	for _, imp := range g.imports {
+		if imp.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldList returns a field list for the given tuple of a mock method.
+func fieldList(g *gen, prefix string, variadic bool, tuple *types.Tuple) *ast.FieldList {
 	if tuple == nil {
 		return nil
 	}
 	fields := make([]*ast.Field, tuple.Len())
-	forTuple(prefix, tuple, func(i int, name string, param *types.Var) {
+	g.forMethodTuple(prefix, tuple, func(i int, name string, param *types.Var) {
 		fields[i] = &ast.Field{}
 		if variadic && i == tuple.Len()-1 {
 			fields[i].Type = &ast.Ellipsis{
-				Elt: ast.NewIdent(param.Type().(*types.Slice).Elem().String()),
+				Elt: ast.NewIdent(g.typeString(param.Type().(*types.Slice).Elem())),
 			}
 		} else {
-			fields[i].Type = ast.NewIdent(param.Type().String())
+			fields[i].Type = ast.NewIdent(g.typeString(param.Type()))
 		}
 
 		if name == "" {
@@ -686,40 +1585,108 @@ type importInfo struct {
 
 // gen is the file-wide generator state.
 type gen struct {
-	pkg         *packages.Package
-	buf         bytes.Buffer
-	imports     map[string]importInfo
-	anonImports map[string]bool
-	values      map[ast.Expr]string
-	funcs       map[string]struct{}
+	pkg          *packages.Package
+	buf          bytes.Buffer
+	importBuf    bytes.Buffer
+	imports      map[string]importInfo
+	anonImports  map[string]bool
+	values       map[ast.Expr]string
+	funcs        map[string]struct{}
+	methods      map[string]map[string]*types.Signature
+	typeParams   map[string]*ast.FieldList
+	only         map[string]map[string]bool
+	partial      bool
+	typed        bool
+	bounded      bool
+	external     bool
+	forward      bool
+	stubTag      string
+	outputSuffix string
+	generatorCmd string
 }
 
 func newGen(pkg *packages.Package) *gen {
 	return &gen{
-		pkg:         pkg,
-		anonImports: make(map[string]bool),
-		imports:     make(map[string]importInfo),
-		values:      make(map[ast.Expr]string),
-		funcs:       make(map[string]struct{}),
+		pkg:          pkg,
+		anonImports:  make(map[string]bool),
+		imports:      make(map[string]importInfo),
+		values:       make(map[ast.Expr]string),
+		funcs:        make(map[string]struct{}),
+		methods:      make(map[string]map[string]*types.Signature),
+		typeParams:   make(map[string]*ast.FieldList),
+		only:         make(map[string]map[string]bool),
+		stubTag:      DefaultStubTag,
+		outputSuffix: DefaultOutputSuffix,
+		generatorCmd: DefaultGeneratorCmd,
+	}
+}
+
+// structType returns the type of the mock struct with the given name,
+// instantiated with its own type parameters when it is generic.
+func (g *gen) structType(structName string) ast.Expr {
+	typeParams := g.typeParams[structName]
+	if typeParams == nil {
+		return ast.NewIdent(structName)
+	}
+	var indices []ast.Expr
+	for _, field := range typeParams.List {
+		for _, name := range field.Names {
+			indices = append(indices, ast.NewIdent(name.Name))
+		}
+	}
+	if len(indices) == 1 {
+		return &ast.IndexExpr{X: ast.NewIdent(structName), Index: indices[0]}
+	}
+	return &ast.IndexListExpr{X: ast.NewIdent(structName), Indices: indices}
+}
+
+// localPkg returns the package that the generated source belongs to, or nil
+// when it belongs to the external test package of the package being
+// generated, which can only reference the exported identifiers of the package.
+func (g *gen) localPkg() *types.Package {
+	if g.external {
+		return nil
+	}
+	return g.pkg.Types
+}
+
+// fieldType returns the type expression of a field copied from a stub file,
+// or of an interface marked by a directive.  When generating the external
+// test package, the expression is rendered from typ instead, as it may
+// reference the identifiers of the package unqualified.
+func (g *gen) fieldType(expr ast.Expr, typ types.Type) ast.Expr {
+	if !g.external {
+		return *clone(&expr)
 	}
+	return ast.NewIdent(g.typeString(typ))
 }
 
 func (g *gen) addDecl(name fmt.Stringer, decl ast.Decl) error {
 	if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+		// Imports copied from more than one stub file are merged, omitting
+		// those already copied, and written before all other declarations.
+		specs := make([]ast.Spec, 0, len(genDecl.Specs))
 		for _, spec := range genDecl.Specs {
 			importSpec := spec.(*ast.ImportSpec)
 			var name string
 			if importSpec.Name != nil {
 				name = importSpec.Name.Name
 			} else {
+				path, err := strconv.Unquote(importSpec.Path.Value)
+				if err != nil {
+					return fmt.Errorf("%s: invalid import path: %w", g.pkg.Fset.Position(importSpec.Pos()), err)
+				}
 				var ok bool
-				name, ok = g.resolvePackageName(importSpec.Path.Value)
+				name, ok = g.resolvePackageName(path)
 				if !ok {
 					continue
 				}
 			}
 			if name != "_" {
 				imp, ok := g.imports[importSpec.Path.Value]
+				if ok && imp.copied && imp.name == name {
+					continue
+				}
 				if ok {
 					imp.copied = true
 				} else {
@@ -731,10 +1698,37 @@ func (g *gen) addDecl(name fmt.Stringer, decl ast.Decl) error {
 				}
 				g.imports[importSpec.Path.Value] = imp
 			}
+			specs = append(specs, spec)
+		}
+		if len(specs) == 0 {
+			return nil
+		}
+		merged := *genDecl
+		merged.Specs = specs
+		if len(specs) == 1 && len(genDecl.Specs) > 1 {
+			merged.Lparen, merged.Rparen = token.NoPos, token.NoPos
 		}
+		var buf bytes.Buffer
+		if err := format.Node(&buf, g.pkg.Fset, &merged); err != nil {
+			return fmt.Errorf("%s: error formatting import: %w", g.pkg.Fset.Position(decl.Pos()), err)
+		}
+		g.importBuf.Write(buf.Bytes())
+		g.importBuf.WriteString("\n\n")
+		return nil
 	}
 	g.addFunc(decl)
 	var buf bytes.Buffer
+	if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Doc != nil && !funcDecl.Doc.Pos().IsValid() {
+		// A generated doc comment has no position, so the printer would
+		// write it after the func keyword rather than before it.
+		for _, comment := range funcDecl.Doc.List {
+			buf.WriteString(comment.Text)
+			buf.WriteByte('\n')
+		}
+		undocumented := *funcDecl
+		undocumented.Doc = nil
+		decl = &undocumented
+	}
 	if err := format.Node(&buf, g.pkg.Fset, decl); err != nil {
 		if name == nil {
 			name = g.pkg.Fset.Position(decl.Pos())
@@ -772,25 +1766,65 @@ func (g *gen) addFunc(decl ast.Decl) (funcDecl *ast.FuncDecl, ok bool) {
 	return
 }
 
+// resolvePackageName returns the name of the imported package with the given
+// path.  The path of a vendored package is matched without its vendor
+// directory prefix, so that the canonical import path is always used.
 func (g *gen) resolvePackageName(path string) (string, bool) {
 	for _, pkg := range g.pkg.Imports {
-		if pkg.PkgPath == path {
+		if pkg.PkgPath == path || unvendoredPath(pkg.PkgPath) == path {
 			return pkg.Name, true
 		}
 	}
 	return "", false
 }
 
+// unvendoredPath returns the given package path without any vendor directory
+// prefix.
+func unvendoredPath(path string) string {
+	if i := strings.LastIndex(path, "/vendor/"); i >= 0 {
+		return path[i+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(path, "vendor/")
+}
+
+// resolveImportName returns the name that the package with the given name
+// and path is imported with in the generated source, adding the import if
+// needed.  Each package referenced by the generated source is imported once,
+// and a name already used by the import of another package is suffixed with
+// a number, such as template2, so that the imports do not collide.
 func (g *gen) resolveImportName(name, path string) string {
-	imp, ok := g.imports[fmt.Sprintf("%q", path)]
-	if !ok {
-		imp = importInfo{
-			name:    name,
-			differs: true,
-		}
-		g.imports[path] = imp
+	if imp, ok := g.imports[strconv.Quote(path)]; ok {
+		// copied from a stub file
+		return imp.name
+	}
+	if imp, ok := g.imports[path]; ok {
+		return imp.name
+	}
+	unique := name
+	for i := 2; g.isImportName(unique); i++ {
+		unique = name + strconv.Itoa(i)
 	}
-	return imp.name
+	g.imports[path] = importInfo{
+		name:    unique,
+		differs: true,
+	}
+	return unique
+}
+
+// typeString returns the representation of typ in the generated source, where
+// the identifiers of other packages are qualified by the names they are
+// imported with.
+func (g *gen) typeString(typ types.Type) string {
+	return types.TypeString(typ, g.qualifier)
+}
+
+// qualifier returns the name that the given package is imported with in the
+// generated source, adding the import if needed.
+func (g *gen) qualifier(pkg *types.Package) string {
+	if pkg == g.localPkg() {
+		return ""
+	}
+	return g.resolveImportName(pkg.Name(), pkg.Path())
 }
 
 func (g *gen) addInterfaceAssertion(ifaceType, structName ast.Expr) error {
@@ -825,19 +1859,46 @@ func (g *gen) addInterfaceAssertion(ifaceType, structName ast.Expr) error {
 }
 
 // frame bakes the built up source body into an unformatted Go source file.
-func (g *gen) frame(tags string) []byte {
+func (g *gen) frame(tags, nolint string) []byte {
 	if g.buf.Len() == 0 {
 		return nil
 	}
 	var buf bytes.Buffer
+	var args string
+	if g.stubTag != DefaultStubTag {
+		args += fmt.Sprintf(" -stubtag %s", g.stubTag)
+	}
 	if len(tags) > 0 {
-		tags = fmt.Sprintf(" gen -tags %q", tags)
+		args += fmt.Sprintf(" -tags %q", tags)
+	}
+	if g.outputSuffix != DefaultOutputSuffix {
+		args += fmt.Sprintf(" -output-suffix %s", g.outputSuffix)
+	}
+	if g.generatorCmd != DefaultGeneratorCmd {
+		args += fmt.Sprintf(" -generator-cmd %q", g.generatorCmd)
+	}
+	if g.forward {
+		args += " -forward"
+	}
+	if g.external {
+		args += " -xtest"
+	}
+	if len(args) > 0 {
+		args = " gen" + args
 	}
 	buf.WriteString("// Code generated by vermockgen. DO NOT EDIT.\n\n")
-	buf.WriteString("//go:generate go run -mod=mod github.com/Versent/go-vermock/cmd/vermockgen" + tags + "\n")
-	buf.WriteString("//+build !vermockstub\n\n")
+	buf.WriteString("//go:generate " + g.generatorCmd + args + "\n")
+	buf.WriteString("//go:build !" + g.stubTag + "\n\n")
+	if len(nolint) > 0 {
+		// A directive immediately before the package clause applies to the
+		// whole file.
+		buf.WriteString("//nolint:" + nolint + "\n")
+	}
 	buf.WriteString("package ")
 	buf.WriteString(g.pkg.Name)
+	if g.external {
+		buf.WriteString("_test")
+	}
 	buf.WriteString("\n\n")
 	imps := make([]string, 0, len(g.imports))
 	for path, imp := range g.imports {
@@ -872,6 +1933,7 @@ func (g *gen) frame(tags string) []byte {
 		}
 		buf.WriteString(")\n\n")
 	}
+	buf.Write(g.importBuf.Bytes())
 	buf.Write(g.buf.Bytes())
 	return buf.Bytes()
 }
@@ -3,11 +3,13 @@ package mock_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -30,11 +32,59 @@ func TestGenerate(t *testing.T) {
 		t,
 		context.Background(),
 		engine,
-		[]string{"MUT=" + mutdir},
+		[]string{
+			"PATH=" + os.Getenv("PATH"),
+			"HOME=" + os.Getenv("HOME"),
+			"MUT=" + mutdir,
+		},
 		"testdata/*.txt",
 	)
 }
 
+func TestWithEnvMap(t *testing.T) {
+	var opts mock.GenerateOptions
+	err := mock.WithArgs(
+		mock.WithEnv([]string{"GOOS=linux", "GOFLAGS=-mod=mod"}),
+		mock.WithEnvMap(map[string]string{"GOOS": "darwin", "GOARCH": "arm64"}),
+	)(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"GOOS=linux", "GOFLAGS=-mod=mod", "GOARCH=arm64", "GOOS=darwin"}
+	if !reflect.DeepEqual(opts.Env, want) {
+		t.Errorf("expected env %q, got %q", want, opts.Env)
+	}
+}
+
+func TestGenerateResult_Failed(t *testing.T) {
+	listErr := errors.New("pattern missing.txt: no matching files found")
+	for _, tc := range []struct {
+		name   string
+		errs   []error
+		failed bool
+	}{
+		{name: "no errors"},
+		{name: "warning", errs: []error{mock.Warning{Err: listErr}}},
+		{name: "error", errs: []error{listErr}, failed: true},
+		{name: "warning and error", errs: []error{mock.Warning{Err: listErr}, listErr}, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := mock.GenerateResult{Errs: tc.errs}
+			if got := result.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+
+	warning := mock.Warning{Err: listErr}
+	if !errors.Is(warning, listErr) {
+		t.Error("expected warning to wrap the error")
+	}
+	if want := "warning: " + listErr.Error(); warning.Error() != want {
+		t.Errorf("expected %q, got %q", want, warning.Error())
+	}
+}
+
 type genCmd struct{}
 
 func (m *genCmd) Run(s *script.State, args ...string) (script.WaitFunc, error) {
@@ -42,13 +92,14 @@ func (m *genCmd) Run(s *script.State, args ...string) (script.WaitFunc, error) {
 	stderr := &bytes.Buffer{}
 	f := flag.NewFlagSet("gen", flag.ContinueOnError)
 	f.SetOutput(stderr)
+	l := log.New(stderr, "vermockgen: ", 0)
+	genCmd := vermockgen.NewGenCmd(l, f)
 	err := f.Parse(args)
 	if err != nil {
 		return nil, err
 	}
-	l := log.New(stderr, "vermockgen: ", 0)
-	genCmd := vermockgen.NewGenCmd(l, f)
-	status := genCmd.Execute(s.Context(), f, mock.WithDir(s.Getwd()))
+	env := append(os.Environ(), s.Environ()...)
+	status := genCmd.Execute(s.Context(), f, mock.WithDir(s.Getwd()), mock.WithEnv(env))
 	return func(s *script.State) (_, _ string, err error) {
 		if status != 0 {
 			err = fmt.Errorf("exit status %d", status)
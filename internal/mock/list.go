@@ -0,0 +1,145 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Mockable is a mock struct that Generate would generate mock methods for, as
+// found by List.
+type Mockable struct {
+	// PkgPath is the package's PkgPath.
+	PkgPath string
+	// Name is the name of the mock struct.
+	Name string
+	// Position is the position of the declaration of the stub struct, or of
+	// the interface marked with a //vermock:mock directive.
+	Position token.Position
+	// Interfaces are the interfaces that the mock struct embeds, qualified
+	// by package name when declared in another package.
+	Interfaces []string
+	// Methods are the methods listed by a //vermock:methods directive on
+	// the stub struct, sorted by name, or nil if all of the methods of the
+	// interfaces are mocked.
+	Methods []string
+}
+
+// List returns the mock structs of the packages matching the given patterns,
+// that is, each struct type declared in a stub file that embeds at least one
+// interface, and each interface type marked with a //vermock:mock directive,
+// sorted by PkgPath and Name.  A //vermock:methods directive is checked as by
+// Generate.  Only the Dir, Env, StubTag and Tags of opts are used, and nothing
+// is written.  Like the Errs of a GenerateResult, any Warning returned does
+// not prevent the mock structs from being listed.
+func List(ctx context.Context, patterns []string, opts GenerateOptions) ([]Mockable, []error) {
+	if opts.StubTag == "" {
+		opts.StubTag = DefaultStubTag
+	}
+	tags := "-tags=" + opts.StubTag
+	if opts.Tags != "" {
+		tags += " " + opts.Tags
+	}
+
+	pkgs, warnings, errs := load(ctx, opts.Dir, opts.Env, []string{tags}, patterns)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// The variants of a package that include its tests repeat the mock
+	// structs of the package, so each is listed once.
+	var mockables []Mockable
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		errs = append(errs, warnings[pkg]...)
+		pkgMockables, pkgErrs := listPackage(pkg, opts.StubTag)
+		errs = append(errs, pkgErrs...)
+		for _, mockable := range pkgMockables {
+			key := mockable.PkgPath + "." + mockable.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			mockables = append(mockables, mockable)
+		}
+	}
+	sort.SliceStable(mockables, func(i, j int) bool {
+		if mockables[i].PkgPath != mockables[j].PkgPath {
+			return mockables[i].PkgPath < mockables[j].PkgPath
+		}
+		return mockables[i].Name < mockables[j].Name
+	})
+	return mockables, errs
+}
+
+// listPackage returns the mock structs of the given package, as described by
+// List.
+func listPackage(pkg *packages.Package, stubTag string) (mockables []Mockable, errs []error) {
+	qualifier := types.RelativeTo(pkg.Types)
+	for _, syntax := range pkg.Syntax {
+		stub := isMockStub(syntax, stubTag)
+		for _, decl := range syntax.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				typ := pkg.TypesInfo.ObjectOf(typeSpec.Name).Type()
+				mockable := Mockable{
+					PkgPath:  pkg.PkgPath,
+					Position: pkg.Fset.Position(typeSpec.Pos()),
+				}
+
+				doc := typeDoc(genDecl, typeSpec)
+				if structName, ok := directiveStructName(doc, typeSpec.Name.Name); ok {
+					if _, ok := typ.Underlying().(*types.Interface); ok && typeSpec.TypeParams == nil {
+						mockable.Name = structName
+						mockable.Interfaces = []string{types.TypeString(typ, qualifier)}
+						mockables = append(mockables, mockable)
+					}
+					continue
+				}
+
+				structType, ok := typ.Underlying().(*types.Struct)
+				if !stub || !ok {
+					continue
+				}
+				only, err := directiveMethods(doc)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", mockable.Position, err))
+					continue
+				}
+				embedded := make(map[string]bool)
+				for i := 0; i < structType.NumFields(); i++ {
+					field := structType.Field(i)
+					if iface, ok := field.Type().Underlying().(*types.Interface); ok && field.Embedded() {
+						mockable.Interfaces = append(mockable.Interfaces, types.TypeString(field.Type(), qualifier))
+						for j := 0; j < iface.NumMethods(); j++ {
+							embedded[iface.Method(j).Name()] = true
+						}
+					}
+				}
+				for methodName := range only {
+					mockable.Methods = append(mockable.Methods, methodName)
+				}
+				sort.Strings(mockable.Methods)
+				for _, methodName := range mockable.Methods {
+					if !embedded[methodName] {
+						errs = append(errs, fmt.Errorf("%s: %s directive lists method %s, which %s does not embed", mockable.Position, methodsDirective, methodName, typeSpec.Name))
+					}
+				}
+				if len(mockable.Interfaces) > 0 {
+					mockable.Name = typeSpec.Name.Name
+					mockables = append(mockables, mockable)
+				}
+			}
+		}
+	}
+	return mockables, errs
+}
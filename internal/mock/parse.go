@@ -16,7 +16,13 @@ import (
 // env is nil or empty, the current environment is used.
 // In case of duplicate environment variables, the last one in the list
 // takes precedence.
-func load(ctx context.Context, wd string, env []string, buildflags []string, patterns []string) ([]*packages.Package, []error) {
+//
+// Errors that prevent a package from being type checked, such as a
+// ParseError or TypeError, are returned as errs, as is a ListError of a
+// package without any source, such as one that is missing.  Any other
+// ListError is returned as a warning of the package, wrapped in Warning, as
+// the package may still be generated.
+func load(ctx context.Context, wd string, env []string, buildflags []string, patterns []string) (pkgs []*packages.Package, warnings map[*packages.Package][]error, errs []error) {
 	cfg := &packages.Config{
 		Context:    ctx,
 		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports | packages.NeedTypes | packages.NeedTypesSizes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedDeps,
@@ -31,16 +37,35 @@ func load(ctx context.Context, wd string, env []string, buildflags []string, pat
 	}
 	pkgs, err := packages.Load(cfg, escaped...)
 	if err != nil {
-		return nil, []error{err}
+		return nil, nil, []error{err}
 	}
-	var errs []error
+	warnings = make(map[*packages.Package][]error)
 	for _, p := range pkgs {
 		for _, e := range p.Errors {
+			if e.Kind == packages.ListError && len(p.Syntax) > 0 {
+				warnings[p] = append(warnings[p], Warning{Err: e})
+				continue
+			}
 			errs = append(errs, e)
 		}
 	}
 	if len(errs) > 0 {
-		return nil, errs
+		return nil, nil, errs
 	}
-	return pkgs, nil
+	return pkgs, warnings, nil
+}
+
+// Warning is an error that does not prevent a package from being generated,
+// such as an error listing the package, which is reported in
+// GenerateResult.Errs alongside the generated Content.
+type Warning struct {
+	Err error
+}
+
+func (w Warning) Error() string {
+	return "warning: " + w.Err.Error()
+}
+
+func (w Warning) Unwrap() error {
+	return w.Err
 }
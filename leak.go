@@ -0,0 +1,31 @@
+package vermock
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// EnableLeakCheck runs the tests of m and then verifies that no mocks remain
+// in the registry, as would happen if the cleanup of a mock never ran.  It is
+// intended to be called from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(vermock.EnableLeakCheck(m))
+//	}
+//
+// Any leaked mocks are reported to stderr and, if the tests otherwise passed,
+// the returned exit code is 1.
+func EnableLeakCheck(m *testing.M) int {
+	code := m.Run()
+	if leaked := ActiveMocks(); len(leaked) > 0 {
+		fmt.Fprintln(os.Stderr, "vermock: leaked mocks:")
+		for _, desc := range leaked {
+			fmt.Fprintln(os.Stderr, "\t"+desc)
+		}
+		if code == 0 {
+			code = 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,45 @@
+package vermock_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+// leakCheckEnv is set in the environment of the test binary when it is run by
+// TestEnableLeakCheck.
+const leakCheckEnv = "VERMOCK_LEAK_CHECK"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(leakCheckEnv) != "" {
+		os.Exit(vermock.EnableLeakCheck(m))
+	}
+	os.Exit(m.Run())
+}
+
+func TestLeakedMock(t *testing.T) {
+	if os.Getenv(leakCheckEnv) == "" {
+		t.Skip("only run by TestEnableLeakCheck")
+	}
+	// the cleanup of a mock constructed with a zero testing.T never runs
+	_ = vermock.New(&testing.T{}, vermock.WithName[mockCache]("leaked"))
+}
+
+func TestEnableLeakCheck(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLeakedMock$")
+	cmd.Env = append(os.Environ(), leakCheckEnv+"=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Fatalf("expected leak check to fail, got %v", err)
+	}
+	const want = "vermock: leaked mocks:\n\t*vermock_test.mockCache \"leaked\"\n"
+	if !strings.Contains(stderr.String(), want) {
+		t.Errorf("expected %q in stderr, got %q", want, stderr.String())
+	}
+}
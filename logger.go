@@ -0,0 +1,45 @@
+package vermock
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// callLoggingOff holds whether the trace of calls logged with t.Logf is
+// disabled, as set by SetCallLogging.
+var callLoggingOff atomic.Bool
+
+// SetCallLogging enables or disables the trace of each call, such as "call to
+// Get: 0/0", that the mocks log with t.Logf.  It is enabled by default.  The
+// trace written to the writer given to WithLogger is not affected, nor are
+// fails.  SetCallLogging applies to all mocks, so it is intended to be called
+// from TestMain.
+func SetCallLogging(enabled bool) {
+	callLoggingOff.Store(!enabled)
+}
+
+// WithLogger makes the mock write its trace of each call, such as "call to
+// Get: 0/0", to w rather than logging it with t.Logf.  Given io.Discard, the
+// trace is silenced.  Fails are still reported to the testing.TB of the mock.
+func WithLogger[T any](w io.Writer) Option[T] {
+	return func(key *T) {
+		lookup(key).logger = w
+	}
+}
+
+// logf writes a line of the trace of the calls of the mock, to the writer
+// given to WithLogger, if any, or otherwise with t.Logf unless disabled by
+// SetCallLogging.
+func (m *mock) logf(format string, args ...any) {
+	m.Helper()
+	if m.logger == nil {
+		if !callLoggingOff.Load() {
+			m.Logf(format, args...)
+		}
+		return
+	}
+	m.logMu.Lock()
+	defer m.logMu.Unlock()
+	fmt.Fprintf(m.logger, format+"\n", args...)
+}
@@ -0,0 +1,59 @@
+package vermock_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	cache := vermock.New(t,
+		vermock.WithLogger[mockCache](&buf),
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+		vermock.WithDefault[mockCache]("Delete", func(key string) {}),
+	)
+	cache.Delete("foo")
+	cache.Delete("bar")
+	if want := "call to Delete: 0/0\ndefault call to Delete: 1\n"; buf.String() != want {
+		t.Errorf("unexpected log: expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWithLogger_discard(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.WithLogger[mockCache](io.Discard),
+	)
+	cache.Delete("foo")
+	if !mockT.Failed() {
+		t.Error("expected failure for unexpected call")
+	}
+}
+
+func TestSetCallLogging(t *testing.T) {
+	defer vermock.SetCallLogging(true)
+
+	for _, enabled := range []bool{true, false} {
+		vermock.SetCallLogging(enabled)
+		mockT := &logT{}
+		var buf bytes.Buffer
+		cache := vermock.New(mockT,
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		)
+		logged := vermock.New(mockT,
+			vermock.WithLogger[mockCache](&buf),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		)
+		cache.Delete("foo")
+		logged.Delete("foo")
+		if got := len(mockT.logs) > 0; got != enabled {
+			t.Errorf("expected logging to be %v, got %q", enabled, mockT.logs)
+		}
+		if buf.Len() == 0 {
+			t.Error("expected the trace to be written to the logger")
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package vermock
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ArgMatcher matches an argument of a call to a method of a mock.
+type ArgMatcher interface {
+	// Match reports whether the argument matches.  The argument is invalid
+	// when it is an untyped nil.
+	Match(arg reflect.Value) bool
+	// String describes the matcher for failure messages.
+	String() string
+}
+
+// Match registers matchers for the arguments of every call of the method with
+// the given name, in the same order as the arguments.  The arguments of each
+// call are checked before the delegate is called, and each argument that does
+// not match is marked as a fail.  A call with an argument that does not match
+// returns zero values, or the fail as its error result, without calling the
+// delegate or using up the expectation.  The last argument of a variadic
+// method is matched as a slice.
+func Match[T any](name string, matchers ...ArgMatcher) Option[T] {
+	return func(key *T) {
+		delegate := delegateByName(lookup(key), name)
+		delegate.Lock()
+		defer delegate.Unlock()
+		delegate.matchers = matchers
+	}
+}
+
+// matchArgs returns the failures of the given arguments to a call to the
+// method with the given name.
+func matchArgs(name string, matchers []ArgMatcher, in []reflect.Value) (failures []string) {
+	for i, matcher := range matchers {
+		if i >= len(in) {
+			failures = append(failures, fmt.Sprintf("argument %d to %s did not match: expected %s, got no argument", i+1, name, matcher))
+			continue
+		}
+		if !matcher.Match(in[i]) {
+			failures = append(failures, fmt.Sprintf("argument %d to %s did not match: expected %s, got %#v", i+1, name, matcher, valueOf(in[i])))
+		}
+	}
+	return
+}
+
+// valueOf returns the value held by v, or nil if v is invalid.
+func valueOf(v reflect.Value) any {
+	if v.IsValid() && v.CanInterface() {
+		return v.Interface()
+	}
+	return nil
+}
+
+// Any returns an ArgMatcher that matches any argument.
+func Any() ArgMatcher {
+	return anyMatcher{}
+}
+
+type anyMatcher struct{}
+
+func (anyMatcher) Match(reflect.Value) bool { return true }
+
+func (anyMatcher) String() string { return "Any()" }
+
+// Eq returns an ArgMatcher that matches an argument that is deeply equal to
+// want.
+func Eq(want any) ArgMatcher {
+	return eqMatcher{want}
+}
+
+type eqMatcher struct {
+	want any
+}
+
+func (m eqMatcher) Match(arg reflect.Value) bool {
+	return reflect.DeepEqual(valueOf(arg), m.want)
+}
+
+func (m eqMatcher) String() string { return fmt.Sprintf("Eq(%#v)", m.want) }
+
+// AnyOf returns an ArgMatcher that matches an argument that is deeply equal to
+// any of the given values.
+func AnyOf(values ...any) ArgMatcher {
+	return anyOfMatcher(values)
+}
+
+type anyOfMatcher []any
+
+func (m anyOfMatcher) Match(arg reflect.Value) bool {
+	for _, want := range m {
+		if (eqMatcher{want}).Match(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m anyOfMatcher) String() string {
+	values := make([]string, len(m))
+	for i, want := range m {
+		values[i] = fmt.Sprintf("%#v", want)
+	}
+	return "AnyOf(" + strings.Join(values, ", ") + ")"
+}
+
+// Regexp returns an ArgMatcher that matches a string argument that matches
+// the regular expression pattern.  The pattern is compiled once, when the
+// matcher is created.
+// Panics if the pattern is not a valid regular expression.
+func Regexp(pattern string) ArgMatcher {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("vermock.Regexp: %v", err))
+	}
+	return regexpMatcher{re}
+}
+
+type regexpMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m regexpMatcher) Match(arg reflect.Value) bool {
+	return arg.IsValid() && arg.Kind() == reflect.String && m.re.MatchString(arg.String())
+}
+
+func (m regexpMatcher) String() string { return fmt.Sprintf("Regexp(%q)", m.re) }
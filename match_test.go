@@ -0,0 +1,103 @@
+package vermock_test
+
+import (
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		matchers []vermock.ArgMatcher
+		failed   bool
+	}{
+		{name: "none"},
+		{name: "any", matchers: []vermock.ArgMatcher{vermock.Any(), vermock.Any()}},
+		{name: "eq", matchers: []vermock.ArgMatcher{vermock.Eq("foo"), vermock.Eq("bar")}},
+		{name: "not eq", matchers: []vermock.ArgMatcher{vermock.Eq("foo"), vermock.Eq("baz")}, failed: true},
+		{name: "any of", matchers: []vermock.ArgMatcher{vermock.AnyOf("baz", "foo")}},
+		{name: "not any of", matchers: []vermock.ArgMatcher{vermock.AnyOf("bar", "baz")}, failed: true},
+		{name: "regexp", matchers: []vermock.ArgMatcher{vermock.Regexp("^fo+$")}},
+		{name: "not regexp", matchers: []vermock.ArgMatcher{vermock.Regexp("^ba")}, failed: true},
+		{name: "regexp any", matchers: []vermock.ArgMatcher{vermock.Any(), vermock.Regexp("^b")}},
+		{name: "too many", matchers: []vermock.ArgMatcher{vermock.Any(), vermock.Any(), vermock.Any()}, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			called := false
+			cache := vermock.New(mockT,
+				vermock.Match[mockCache]("Put", tc.matchers...),
+				vermock.Expect[mockCache]("Put", func(key string, value any) error {
+					called = true
+					return nil
+				}),
+			)
+			_ = cache.Put("foo", "bar")
+			if called == tc.failed {
+				t.Errorf("expected called to be %v, got %v", !tc.failed, called)
+			}
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
+
+func TestMatch_mismatchNotConsumed(t *testing.T) {
+	mockT := &testing.T{}
+	var keys []string
+	cache := vermock.New(mockT,
+		vermock.Match[mockCache]("Put", vermock.Eq("foo")),
+		vermock.Expect[mockCache]("Put", func(key string, value any) error {
+			keys = append(keys, key)
+			return nil
+		}),
+	)
+	if err := cache.Put("bar", 1); err == nil {
+		t.Error("expected error for mismatched call")
+	}
+	if err := cache.Put("foo", 2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "foo" {
+		t.Errorf("unexpected calls of the delegate: %q", keys)
+	}
+	if n := vermock.CallCountOf(cache, "Put"); n != 1 {
+		t.Errorf("expected 1 call, got %d", n)
+	}
+	vermock.AssertExpectedCalls(mockT, cache)
+	if !mockT.Failed() {
+		t.Error("expected failure for mismatched call")
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	t.Run("prefix", func(t *testing.T) {
+		mockT := &testing.T{}
+		cache := vermock.New(mockT,
+			vermock.Match[mockCache]("Get", vermock.Regexp("^user/")),
+			vermock.ExpectMany[mockCache]("Get", func(key string) (any, bool) {
+				return nil, false
+			}),
+		)
+		cache.Get("user/1")
+		cache.Get("user/2")
+		if mockT.Failed() {
+			t.Error("expected keys with prefix to match")
+		}
+		cache.Get("group/1")
+		if !mockT.Failed() {
+			t.Error("expected key without prefix not to match")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != "vermock.Regexp: error parsing regexp: missing closing ): `(`" {
+				t.Errorf("unexpected panic: %v", r)
+			}
+		}()
+		vermock.Regexp("(")
+	})
+}
@@ -4,16 +4,31 @@ package vermock
 
 import (
 	"fmt"
+	"io"
 	"reflect"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 var (
 	// registry holds the active mock objects.
 	registry = make(map[any]*mock)
+	// registryMu guards registry.
+	registryMu sync.RWMutex
 )
 
+// lookup returns the active mock object for the given key, or nil if there
+// is none.  It is safe to call from multiple goroutines.
+func lookup(key any) *mock {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[key]
+}
+
 // Delegates maps function names to their Delegate implementations.
 type Delegates = map[string]*Delegate
 
@@ -33,23 +48,48 @@ type mock struct {
 	testing.TB
 	sync.Mutex
 	Delegates
+	// ordered holds whether expectations are being registered in order, and
+	// the Sequence that they are ordered by.
 	ordered
+	allowedCallers []string
+	timestamps     bool
+	strict         bool
+	failFast       bool
+	contextCheck   bool
+	concurrency    bool
+	name           string
+	exclusive      [][]string
+	formatter      FailureFormatter
+	logger         io.Writer
+	// keyType is the type of the key of the mock, whose methods are mocked.
+	// It is nil for a mock that was not created by New.
+	keyType reflect.Type
+	// prefixes maps the prefixes registered with ExpectPrefix to their
+	// Delegates.
+	prefixes Delegates
+	// log and the times of calls are guarded by logMu rather than the mock's
+	// lock, as calls are recorded while the lock of a Delegate is held.
+	logMu    sync.Mutex
+	log      []CallRecord
+	started  time.Time
+	lastCall time.Time
 }
 
 // New creates a new mock object of type T and applies the given options.
-// It panics if a mock for a zero-sized type is constructed more than once.
+// Each mock object is distinct, even if T is a zero-sized type.
 func New[T any](t testing.TB, opts ...Option[T]) *T {
-	key := new(T)
+	key := newKey[T]()
 	mock := &mock{
 		TB:        t,
 		Delegates: Delegates{},
+		ordered:   ordered{seq: NewSequence()},
+		keyType:   reflect.TypeOf(key),
 	}
-	if _, ok := registry[key]; ok {
-		panic(fmt.Sprintf("vermock.New: zero-sized type used to construct more than one mock: %T", key))
-	}
+	registryMu.Lock()
 	registry[key] = mock
+	registryMu.Unlock()
 	t.Cleanup(func() {
-		delete(registry, key)
+		Close(key)
 	})
 	for _, opt := range opts {
 		if opt == nil {
@@ -57,14 +97,28 @@ func New[T any](t testing.TB, opts ...Option[T]) *T {
 		}
 		opt(key)
 	}
-	mock.ordinal = 0
 	return key
 }
 
+// newKey allocates a new T to be the key of a mock in the registry.  Pointers
+// to distinct zero-sized variables may be equal, so a zero-sized T is
+// allocated together with a sentinel byte, to give it an address of its own.
+func newKey[T any]() *T {
+	if reflect.TypeOf((*T)(nil)).Elem().Size() > 0 {
+		return new(T)
+	}
+	return &new(struct {
+		key      T
+		sentinel byte
+	}).key
+}
+
 // Expect registers a function to be called exactly once when a method with the
 // given name is invoked on the mock object.
 // The function signature of fn must match the named method signature,
-// except that the first argument may optionally be a testing.TB or *testing.T.
+// except that the first argument may optionally be a testing.TB or *testing.T,
+// and may be followed by an argument of type CallCount, which is passed the
+// number of times the method has been called (starting at 0).
 // Panics if fn is not a function.
 func Expect[T any](name string, fn any) Option[T] {
 	funcType := reflect.TypeOf(fn)
@@ -72,19 +126,125 @@ func Expect[T any](name string, fn any) Option[T] {
 		panic(fmt.Sprintf("vermock.Expect: expected function, got %T", fn))
 	}
 	return func(key *T) {
-		mock := registry[key]
+		mock := lookup(key)
 		mock.Helper()
 		delegate := delegateByName(mock, name)
-		if mock.inOrder {
-			mock.ordinal++
-		}
 		delegate.Append(Value{
 			Value:   reflect.ValueOf(fn),
-			ordered: mock.ordered,
+			ordered: mock.next(name),
 		})
 	}
 }
 
+// ExpectOnce is an alias of Expect, for readability where calls that are
+// expected exactly once are mixed with those expected any number of times.
+func ExpectOnce[T any](name string, fn any) Option[T] {
+	return Expect[T](name, fn)
+}
+
+// ExpectTyped is like Expect, except that the type of fn is a type parameter.
+// This allows generated functions to constrain fn to the signature of the
+// named method, such that a delegate with the wrong signature fails to
+// compile instead of panicking when called.
+func ExpectTyped[T, F any](name string, fn F) Option[T] {
+	return Expect[T](name, fn)
+}
+
+// ExpectMethod is like Expect, except that the method is given as a method
+// expression, such as Cache.Get, or a method value, rather than by name.  The
+// receiver of a method expression must be *T, T or an interface that *T
+// implements, while a method value must be bound to a *T or T.  The
+// signature of fn is validated against the method when the option is
+// created, so that a misspelt method or a delegate with the wrong signature
+// is caught before the mock is used.
+// Panics if method is not a method of *T or fn does not match its signature.
+func ExpectMethod[T any](method, fn any) Option[T] {
+	name := methodName[T](method)
+	funcType := methodFuncType[T]("vermock.ExpectMethod", name)
+	if !delegateMatches(reflect.TypeOf(fn), funcType) {
+		panic(fmt.Sprintf("vermock.ExpectMethod: expected %s, got %T", funcType, fn))
+	}
+	return Expect[T](name, fn)
+}
+
+// methodName returns the name of the method of the given method expression or
+// method value, which must be a method of *T, as described by ExpectMethod.
+func methodName[T any](method any) string {
+	value := reflect.ValueOf(method)
+	if value.Kind() != reflect.Func {
+		panic(fmt.Sprintf("vermock.ExpectMethod: expected method, got %T", method))
+	}
+	ptrType := reflect.TypeOf((*T)(nil))
+	name := runtime.FuncForPC(value.Pointer()).Name()
+	// method values are named like pkg.(*Type).Method-fm
+	name, bound := strings.CutSuffix(name, "-fm")
+	recv := name[:strings.LastIndex(name, ".")]
+	var ok bool
+	if bound {
+		ok = recv == funcTypeName(ptrType) || recv == funcTypeName(ptrType.Elem())
+	} else if funcType := value.Type(); funcType.NumIn() > 0 {
+		in := funcType.In(0)
+		ok = in == ptrType || in == ptrType.Elem() || in.Kind() == reflect.Interface && ptrType.Implements(in)
+	}
+	if !ok {
+		panic(fmt.Sprintf("vermock.ExpectMethod: expected method of %s, got %s", ptrType, name))
+	}
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// funcTypeName returns the name of the given named type, or pointer to a named
+// type, as it qualifies the names of its methods in the runtime, where the
+// type arguments of a generic type are elided.
+func funcTypeName(typ reflect.Type) string {
+	format := "%s.%s"
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+		format = "%s.(*%s)"
+	}
+	name := typ.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i] + "[...]"
+	}
+	return fmt.Sprintf(format, typ.PkgPath(), name)
+}
+
+// delegateMatches reports whether a delegate of type delegateType may be
+// called for a method of type funcType, that is, whether the types are the
+// same after removing an optional leading testing.TB or *testing.T and an
+// optional CallCount.
+func delegateMatches(delegateType, funcType reflect.Type) bool {
+	if delegateType == nil || delegateType.Kind() != reflect.Func {
+		return false
+	}
+	if delegateType == funcType {
+		return true
+	}
+	var skip int
+	if delegateType.NumIn() > 0 && (delegateType.In(0) == tbType || delegateType.In(0) == tType) {
+		skip++
+	}
+	if delegateType.NumIn() > skip && delegateType.In(skip) == callCountType {
+		skip++
+	}
+	if delegateType.NumIn() != funcType.NumIn()+skip {
+		return false
+	}
+	if delegateType.IsVariadic() != funcType.IsVariadic() || delegateType.NumOut() != funcType.NumOut() {
+		return false
+	}
+	for i := 0; i < funcType.NumIn(); i++ {
+		if delegateType.In(i+skip) != funcType.In(i) {
+			return false
+		}
+	}
+	for i := 0; i < funcType.NumOut(); i++ {
+		if delegateType.Out(i) != funcType.Out(i) {
+			return false
+		}
+	}
+	return true
+}
+
 // ExpectMany registers a function to be called at least once for a method with
 // the given name on the mock object.
 // Like Expect, the arguments of fn must match the named method signature and may optionally be
@@ -98,14 +258,209 @@ func ExpectMany[T any](name string, fn any) Option[T] {
 		panic(fmt.Sprintf("vermock.ExpectMany: expected function, got %T", fn))
 	}
 	return func(key *T) {
-		mock := registry[key]
+		mock := lookup(key)
 		mock.Helper()
-		if mock.inOrder {
-			mock.ordinal++
-		}
 		delegateByName(mock, name).Append(multi{
 			Value:   reflect.ValueOf(fn),
-			ordered: mock.ordered,
+			ordered: mock.next(name),
 		})
 	}
 }
+
+// ExpectAtMost is like ExpectMany, except that the method may be called at
+// most max times, including not at all.  A call beyond max is marked as a
+// fail.  Like ExpectMany, it should be the last expectation for the method.
+// Panics if fn is not a function or max is negative.
+func ExpectAtMost[T any](name string, max int, fn any) Option[T] {
+	return expectBetween[T]("vermock.ExpectAtMost", name, 0, max, fn)
+}
+
+// ExpectBetween is like ExpectMany, except that the method must be called at
+// least min and at most max times.  AssertExpectedCalls marks the test as
+// failed when there are fewer than min calls, and a call beyond max is marked
+// as a fail.  Like ExpectMany, it should be the last expectation for the
+// method.
+// Panics if fn is not a function, min is negative or max is less than min.
+func ExpectBetween[T any](name string, min, max int, fn any) Option[T] {
+	return expectBetween[T]("vermock.ExpectBetween", name, min, max, fn)
+}
+
+func expectBetween[T any](caller, name string, min, max int, fn any) Option[T] {
+	funcType := reflect.TypeOf(fn)
+	if funcType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("%s: expected function, got %T", caller, fn))
+	}
+	if min < 0 || max < min {
+		panic(fmt.Sprintf("%s: invalid bounds [%d, %d]", caller, min, max))
+	}
+	return func(key *T) {
+		mock := lookup(key)
+		mock.Helper()
+		delegateByName(mock, name).Append(bounded{
+			multi: multi{
+				Value:   reflect.ValueOf(fn),
+				ordered: mock.next(name),
+			},
+			min: min,
+			max: max,
+		})
+	}
+}
+
+// ExpectTimes registers a function to be called exactly n times when a method
+// with the given name is invoked on the mock object.  It is equivalent to
+// passing the same Expect option n times, so the calls that follow the nth
+// fail as unexpected.  When n is 0 nothing is registered, and like
+// UnusedCache in the examples, any call to the method fails.
+// Panics if fn is not a function or n is negative.
+func ExpectTimes[T any](name string, n int, fn any) Option[T] {
+	funcType := reflect.TypeOf(fn)
+	if funcType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("vermock.ExpectTimes: expected function, got %T", fn))
+	}
+	if n < 0 {
+		panic(fmt.Sprintf("vermock.ExpectTimes: negative count %d", n))
+	}
+	expect := Expect[T](name, fn)
+	return func(key *T) {
+		for i := 0; i < n; i++ {
+			expect(key)
+		}
+	}
+}
+
+// ExpectFlaky registers failFn to be called for the first failFor calls of a
+// method with the given name, and successFn for all of the calls that follow,
+// to simulate a dependency that fails until it recovers.  It is equivalent to
+// ExpectTimes with failFn followed by ExpectMany with successFn, so like
+// ExpectMany, it should be the last expectation for the method.
+// Panics if failFn or successFn is not a function or failFor is negative.
+func ExpectFlaky[T any](name string, failFor int, failFn, successFn any) Option[T] {
+	for _, fn := range []any{failFn, successFn} {
+		if funcType := reflect.TypeOf(fn); funcType == nil || funcType.Kind() != reflect.Func {
+			panic(fmt.Sprintf("vermock.ExpectFlaky: expected function, got %T", fn))
+		}
+	}
+	if failFor < 0 {
+		panic(fmt.Sprintf("vermock.ExpectFlaky: negative count %d", failFor))
+	}
+	return Options(
+		ExpectTimes[T](name, failFor, failFn),
+		ExpectMany[T](name, successFn),
+	)
+}
+
+// Return registers the given values to be returned by exactly one call of the
+// method with the given name, without writing a delegate.  A nil value is the
+// zero value of the corresponding result.
+// Panics if T has no method with the given name, or the number or types of
+// the values do not match the results of the method.
+func Return[T any](name string, values ...any) Option[T] {
+	funcType := methodFuncType[T]("vermock.Return", name)
+	if funcType.NumOut() != len(values) {
+		panic(fmt.Sprintf("vermock.Return: unexpected number of results: expected %d, got %d", funcType.NumOut(), len(values)))
+	}
+	results := make([]reflect.Value, len(values))
+	for i, value := range values {
+		outType := funcType.Out(i)
+		if value == nil {
+			switch outType.Kind() {
+			case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+				results[i] = reflect.Zero(outType)
+				continue
+			}
+		} else if reflect.TypeOf(value).AssignableTo(outType) {
+			results[i] = reflect.ValueOf(value)
+			continue
+		}
+		panic(fmt.Sprintf("vermock.Return: unexpected type %T for result parameter %s", value, reflect.PointerTo(outType)))
+	}
+	fn := reflect.MakeFunc(funcType, func([]reflect.Value) []reflect.Value {
+		return results
+	})
+	return Expect[T](name, fn.Interface())
+}
+
+// methodFuncType returns the type of a function with the signature of the
+// method of *T with the given name, that is, without the receiver.
+// Panics, prefixed by caller, if there is no such method.
+func methodFuncType[T any](caller, name string) reflect.Type {
+	method, ok := reflect.TypeOf((*T)(nil)).MethodByName(name)
+	if !ok {
+		panic(fmt.Sprintf("%s: method %s not found for %T", caller, name, (*T)(nil)))
+	}
+	methodType := method.Type
+	// the receiver is not an argument of the delegate
+	in := make([]reflect.Type, methodType.NumIn()-1)
+	for i := range in {
+		in[i] = methodType.In(i + 1)
+	}
+	out := make([]reflect.Type, methodType.NumOut())
+	for i := range out {
+		out[i] = methodType.Out(i)
+	}
+	return reflect.FuncOf(in, out, methodType.IsVariadic())
+}
+
+// WithStrict makes an unexpected call to a method of the mock panic with an
+// *UnexpectedCallError, carrying the method name and arguments, rather than
+// marking the test as failed.  The panic may be recovered in negative tests.
+func WithStrict[T any]() Option[T] {
+	return func(key *T) {
+		lookup(key).strict = true
+	}
+}
+
+// WithFailFast makes a call to a method of the mock that is marked as a fail,
+// such as an unexpected or out of order call, stop the test with t.Fatal
+// rather than mark it as failed with t.Error, so that the code under test does
+// not continue with the zero results of the call.  As with t.FailNow, the
+// test is only stopped when the method is called from the goroutine running
+// the test; a call from another goroutine exits that goroutine instead.
+func WithFailFast[T any]() Option[T] {
+	return func(key *T) {
+		lookup(key).failFast = true
+	}
+}
+
+// AutoAssert makes the mock assert that all of its expected calls were made,
+// as with AssertExpectedCalls, when the test completes.  The assertion is
+// registered with t.Cleanup, so it runs before the mock is removed from the
+// registry, unless the mock was already removed by Close.
+func AutoAssert[T any]() Option[T] {
+	return func(key *T) {
+		mock := lookup(key)
+		mock.Cleanup(func() {
+			if lookup(key) == nil {
+				return
+			}
+			AssertExpectedCalls(mock.TB, key)
+		})
+	}
+}
+
+// WithName sets a name for the mock, which is used to identify the mock in
+// ActiveMocks.
+func WithName[T any](name string) Option[T] {
+	return func(key *T) {
+		lookup(key).name = name
+	}
+}
+
+// ActiveMocks returns a sorted description of each mock that is currently
+// registered.  Each description is the type of the mock followed by its name
+// when set with WithName.  This is useful for debugging leaked mocks.
+func ActiveMocks() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	active := make([]string, 0, len(registry))
+	for key, mock := range registry {
+		if mock.name == "" {
+			active = append(active, fmt.Sprintf("%T", key))
+		} else {
+			active = append(active, fmt.Sprintf("%T %q", key, mock.name))
+		}
+	}
+	sort.Strings(active)
+	return active
+}
@@ -1,6 +1,10 @@
 package vermock_test
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	vermock "github.com/Versent/go-vermock"
@@ -25,16 +29,12 @@ func TestNew_identity(t *testing.T) {
 	})
 
 	t.Run("zero-sized", func(t *testing.T) {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Error("expected panic")
-			} else if r != "vermock.New: zero-sized type used to construct more than one mock: *vermock_test.T" {
-				t.Error("unexpected panic:", r)
-			}
-		}()
 		type T struct{}
-		_ = vermock.New[T](t)
-		_ = vermock.New[T](t)
+		m1 := vermock.New[T](t)
+		m2 := vermock.New[T](t)
+		if m1 == m2 {
+			t.Error("expected different mocks")
+		}
 	})
 }
 
@@ -88,3 +88,443 @@ func TestNew_Expect(t *testing.T) {
 		t.Error("expected call to Delete delegate")
 	}
 }
+
+func TestNew_ExpectThenExpectMany(t *testing.T) {
+	var got []string
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Load", func(keys ...string) {
+			got = append(got, "once")
+		}),
+		vermock.ExpectMany[mockCache]("Load", func(n vermock.CallCount, keys ...string) {
+			got = append(got, fmt.Sprint("many ", n))
+		}),
+	)
+	cache.Load("foo")
+	cache.Load("bar")
+	cache.Load("baz")
+	vermock.AssertExpectedCalls(t, cache)
+	if want := []string{"once", "many 1", "many 2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected calls %q, got %q", want, got)
+	}
+}
+
+func TestNew_ExpectCallCount_noResults(t *testing.T) {
+	var got []vermock.CallCount
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Load", func(n vermock.CallCount) {
+			got = append(got, n)
+		}),
+		vermock.Expect[mockCache]("Load", func(_ testing.TB, n vermock.CallCount) {
+			got = append(got, n)
+		}),
+		vermock.ExpectTimes[mockCache]("Load", 2, func(_ *testing.T, n vermock.CallCount, keys ...string) {
+			got = append(got, n)
+		}),
+	)
+	cache.Load("foo")
+	cache.Load()
+	cache.Load("foo", "bar")
+	cache.Load()
+	vermock.AssertExpectedCalls(t, cache)
+	if want := []vermock.CallCount{0, 1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected call counts %v, got %v", want, got)
+	}
+}
+
+func TestNew_ExpectCallCount(t *testing.T) {
+	var got []vermock.CallCount
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Delete", func(n vermock.CallCount, key string) {
+			got = append(got, n)
+		}),
+		vermock.Expect[mockCache]("Delete", func(_ testing.TB, n vermock.CallCount, key string) {
+			got = append(got, n)
+		}),
+		vermock.ExpectMethod[mockCache](Cache.Delete, func(n vermock.CallCount, key string) {
+			got = append(got, n)
+		}),
+	)
+	cache.Delete("foo")
+	cache.Delete("foo")
+	cache.Delete("foo")
+	vermock.AssertExpectedCalls(t, cache)
+	if want := []vermock.CallCount{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected call counts %v, got %v", want, got)
+	}
+}
+
+func TestNew_ExpectNoVariadicArgs(t *testing.T) {
+	var got [][]string
+	cache := vermock.New(t,
+		vermock.Expect[mockCache]("Load", func(keys ...string) {
+			got = append(got, keys)
+		}),
+		vermock.Expect[mockCache]("Load", func(_ testing.TB, keys ...string) {
+			got = append(got, keys)
+		}),
+		vermock.Expect[mockCache]("Load", func() {
+			got = append(got, nil)
+		}),
+		vermock.Expect[mockCache]("Load", func(_ testing.TB) {
+			got = append(got, nil)
+		}),
+	)
+	for i := 0; i < 4; i++ {
+		cache.Load()
+	}
+	vermock.AssertExpectedCalls(t, cache)
+	for i, keys := range got {
+		if len(keys) != 0 {
+			t.Errorf("call %d: expected no keys, got %q", i, keys)
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("expected 4 calls, got %d", len(got))
+	}
+}
+
+func TestExpectTimes(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		times  int
+		calls  int
+		failed bool
+	}{
+		{name: "too few", times: 2, calls: 1, failed: true},
+		{name: "exact", times: 2, calls: 2},
+		{name: "too many", times: 2, calls: 3, failed: true},
+		{name: "none", times: 0, calls: 0},
+		{name: "unexpected", times: 0, calls: 1, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			cache := vermock.New(mockT,
+				vermock.ExpectTimes[mockCache]("Delete", tc.times, func(key string) {}),
+			)
+			for i := 0; i < tc.calls; i++ {
+				cache.Delete("foo")
+			}
+			vermock.AssertExpectedCalls(mockT, cache)
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
+
+func TestExpectBetween(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		min, max int
+		calls    int
+		failed   bool
+	}{
+		{name: "too few", min: 1, max: 2, calls: 0, failed: true},
+		{name: "min", min: 1, max: 2, calls: 1},
+		{name: "max", min: 1, max: 2, calls: 2},
+		{name: "too many", min: 1, max: 2, calls: 3, failed: true},
+		{name: "at most none", min: 0, max: 2, calls: 0},
+		{name: "at most exceeded", min: 0, max: 2, calls: 3, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			cache := vermock.New(mockT,
+				vermock.Expect[mockCache]("Delete", func(key string) {}),
+				vermock.ExpectBetween[mockCache]("Delete", tc.min, tc.max, func(key string) {}),
+			)
+			for i := 0; i <= tc.calls; i++ {
+				cache.Delete("foo")
+			}
+			vermock.AssertExpectedCalls(mockT, cache)
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+
+	t.Run("in order", func(t *testing.T) {
+		mockT := &testing.T{}
+		cache := vermock.New(mockT,
+			vermock.ExpectInOrder(
+				vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+				vermock.ExpectAtMost[mockCache]("Delete", 2, func(key string) {}),
+			),
+		)
+		_ = cache.Put("foo", "bar")
+		cache.Delete("foo")
+		cache.Delete("foo")
+		vermock.AssertExpectedCalls(mockT, cache)
+		if mockT.Failed() {
+			t.Error("expected no failure")
+		}
+	})
+}
+
+func TestExpectFlaky(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		failFor int
+		calls   int
+		failed  bool
+	}{
+		{name: "failing", failFor: 2, calls: 2, failed: true},
+		{name: "recovered", failFor: 2, calls: 4},
+		{name: "never failing", failFor: 0, calls: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			unavailable := errors.New("unavailable")
+			cache := vermock.New(mockT,
+				vermock.ExpectFlaky[mockCache]("Put", tc.failFor,
+					func(key string, value any) error {
+						return unavailable
+					},
+					func(key string, value any) error {
+						return nil
+					},
+				),
+			)
+			for i := 0; i < tc.calls; i++ {
+				err := cache.Put("foo", "bar")
+				if i < tc.failFor && err != unavailable {
+					t.Errorf("expected call %d to fail, got %v", i, err)
+				} else if i >= tc.failFor && err != nil {
+					t.Errorf("expected call %d to succeed, got %v", i, err)
+				}
+			}
+			vermock.AssertExpectedCalls(mockT, cache)
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
+
+// cleanupT is a testing.TB that runs its cleanup functions on demand.
+type cleanupT struct {
+	testing.T
+	cleanups []func()
+}
+
+func (t *cleanupT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *cleanupT) runCleanups() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestAutoAssert(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		calls  int
+		failed bool
+	}{
+		{name: "met", calls: 1},
+		{name: "unmet", calls: 0, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &cleanupT{}
+			cache := vermock.New(mockT,
+				vermock.AutoAssert[mockCache](),
+				vermock.Expect[mockCache]("Delete", func(key string) {}),
+			)
+			for i := 0; i < tc.calls; i++ {
+				cache.Delete("foo")
+			}
+			if mockT.Failed() {
+				t.Fatal("expected no failure before cleanup")
+			}
+			mockT.runCleanups()
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
+
+// deleteFoo is the only caller allowed by TestWithAllowedCallers.
+func deleteFoo(cache Cache) {
+	cache.Delete("foo")
+}
+
+func TestWithAllowedCallers(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.WithAllowedCallers[mockCache]("github.com/Versent/go-vermock_test.deleteFoo"),
+		vermock.ExpectMany[mockCache]("Delete", func(key string) {}),
+	)
+
+	deleteFoo(cache)
+	if mockT.Failed() {
+		t.Error("expected no failure for allowed caller")
+	}
+
+	cache.Delete("foo")
+	if !mockT.Failed() {
+		t.Error("expected failure for disallowed caller")
+	}
+}
+
+func TestWithStrict(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT, vermock.WithStrict[mockCache]())
+
+	defer func() {
+		err, ok := recover().(error)
+		var callErr *vermock.UnexpectedCallError
+		if !ok || !errors.As(err, &callErr) {
+			t.Fatalf("expected *vermock.UnexpectedCallError, got %v", err)
+		}
+		if callErr.Name != "Put" {
+			t.Errorf("unexpected name: %q", callErr.Name)
+		}
+		if !reflect.DeepEqual(callErr.Args, []any{"foo", "bar"}) {
+			t.Errorf("unexpected args: %v", callErr.Args)
+		}
+		if mockT.Failed() {
+			t.Error("expected no failure")
+		}
+	}()
+	_ = cache.Put("foo", "bar")
+}
+
+func TestWithFailFast(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT, vermock.WithFailFast[mockCache]())
+
+	var returned bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = cache.Put("foo", "bar")
+		returned = true
+	}()
+	<-done
+	if returned {
+		t.Error("expected unexpected call not to return")
+	}
+	if !mockT.Failed() {
+		t.Error("expected failure")
+	}
+}
+
+func TestActiveMocks(t *testing.T) {
+	const want = `*vermock_test.mockCache "TestActiveMocks"`
+	contains := func(active []string) bool {
+		for _, desc := range active {
+			if desc == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("registered", func(t *testing.T) {
+		_ = vermock.New(t, vermock.WithName[mockCache]("TestActiveMocks"))
+		if active := vermock.ActiveMocks(); !contains(active) {
+			t.Errorf("expected %s in %q", want, active)
+		}
+	})
+
+	if active := vermock.ActiveMocks(); contains(active) {
+		t.Errorf("expected %s to be removed from %q", want, active)
+	}
+}
+
+func TestExpectMethod(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.ExpectMethod[mockCache](Cache.Get, func(key string) (any, bool) {
+			return "bar", true
+		}),
+		vermock.ExpectMethod[mockCache]((*mockCache).Delete, func(_ testing.TB, key string) {}),
+	)
+	if value, ok := cache.Get("foo"); value != "bar" || !ok {
+		t.Errorf("unexpected result: %v, %v", value, ok)
+	}
+	cache.Delete("foo")
+	vermock.AssertExpectedCalls(t, cache)
+
+	t.Run("wrong signature", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != "vermock.ExpectMethod: expected func(string) (interface {}, bool), got func(int) (interface {}, bool)" {
+				t.Errorf("unexpected panic: %v", r)
+			}
+		}()
+		vermock.ExpectMethod[mockCache](Cache.Get, func(key int) (any, bool) {
+			return nil, false
+		})
+	})
+
+	t.Run("method value", func(t *testing.T) {
+		other := vermock.New[mockCache](t)
+		cache := vermock.New(t,
+			vermock.ExpectMethod[mockCache](other.Delete, func(key string) {}),
+		)
+		cache.Delete("foo")
+		vermock.AssertExpectedCalls(t, cache)
+	})
+
+	for name, method := range map[string]any{
+		"wrong receiver":     realCache.Get,
+		"wrong method value": realCache{}.Get,
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r, _ := recover().(string); !strings.HasPrefix(r, "vermock.ExpectMethod: expected method of *vermock_test.mockCache, got ") {
+					t.Errorf("unexpected panic: %v", r)
+				}
+			}()
+			vermock.ExpectMethod[mockCache](method, func(key string) (any, bool) {
+				return nil, false
+			})
+		})
+	}
+}
+
+func TestTryCall(t *testing.T) {
+	stringType := reflect.TypeOf("")
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.WithStrict[mockCache](),
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) {
+			return "bar", true
+		}),
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) {
+			return 1, true
+		}),
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) {
+			return 2, true
+		}),
+	)
+
+	out, err := vermock.TryCall(cache, "Get", []reflect.Type{reflect.TypeOf((*any)(nil)).Elem(), reflect.TypeOf(true)}, reflect.ValueOf("foo"))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out[0].Interface() != "bar" || out[1].Interface() != true {
+		t.Errorf("unexpected results: %v", out)
+	}
+
+	_, err = vermock.TryCall(cache, "Get", []reflect.Type{stringType, reflect.TypeOf(true)}, reflect.ValueOf("foo"))
+	if want := "unexpected type interface {} for result 0: expected string"; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+
+	_, err = vermock.TryCall(cache, "Get", []reflect.Type{stringType}, reflect.ValueOf("foo"))
+	if want := "unexpected number of results: expected 1, got 2"; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+
+	_, err = vermock.TryCall(cache, "Get", []reflect.Type{stringType, reflect.TypeOf(true)}, reflect.ValueOf("foo"))
+	var callErr *vermock.UnexpectedCallError
+	if !errors.As(err, &callErr) || callErr.Name != "Get" {
+		t.Errorf("expected *vermock.UnexpectedCallError for Get, got %v", err)
+	}
+
+	if mockT.Failed() {
+		t.Error("expected no failure")
+	}
+}
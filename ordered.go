@@ -1,13 +1,99 @@
 package vermock
 
+import "sync"
+
+// Sequence holds the ordinals of ordered expectations and calls.  Each mock
+// has a Sequence of its own, used by ExpectInOrder, while a Sequence created
+// with NewSequence may be shared by more than one mock with ExpectInSequence.
+type Sequence struct {
+	sync.Mutex
+	// ordinal holds the ordinal of the last ordered expectation registered,
+	// while calls holds the ordinal of the last ordered call made.
+	ordinal, calls uint
+	// names maps the ordinal of each ordered expectation to the name of its
+	// method.
+	names map[uint]string
+}
+
+// NewSequence creates a Sequence to be shared by the mocks given to
+// ExpectInSequence.
+func NewSequence() *Sequence {
+	return new(Sequence)
+}
+
 type ordered struct {
 	inOrder bool
 	ordinal uint
+	seq     *Sequence
+}
+
+// next returns the ordering of an expectation of the method with the given
+// name registered now, advancing the ordinal of the sequence, and recording
+// the name at the ordinal, if the expectation is ordered.
+func (o ordered) next(name string) ordered {
+	o.seq.Lock()
+	defer o.seq.Unlock()
+	if o.inOrder {
+		o.seq.ordinal++
+		if o.seq.names == nil {
+			o.seq.names = make(map[uint]string)
+		}
+		o.seq.names[o.seq.ordinal] = name
+	}
+	o.ordinal = o.seq.ordinal
+	return o
+}
+
+// call returns the ordinal of the last ordered call of the sequence, after
+// advancing it if the call is ordered.
+func (o ordered) call() uint {
+	o.seq.Lock()
+	defer o.seq.Unlock()
+	if o.inOrder {
+		o.seq.calls++
+	}
+	return o.seq.calls
+}
+
+// nameAt returns the name of the method of the ordered expectation with the
+// given ordinal, or "" if there is none.
+func (s *Sequence) nameAt(ordinal uint) string {
+	s.Lock()
+	defer s.Unlock()
+	return s.names[ordinal]
+}
+
+// reached reports whether the last ordered call of the sequence has reached
+// the ordinal of an ordered expectation, and returns the ordinal of the last
+// ordered call.  An expectation that is not ordered is always reached.
+func (o ordered) reached() (bool, uint) {
+	if !o.inOrder || o.seq == nil {
+		return true, 0
+	}
+	o.seq.Lock()
+	defer o.seq.Unlock()
+	return o.seq.calls >= o.ordinal, o.seq.calls
+}
+
+// orderedOf returns the ordering of the given Callable, or false if it has
+// none.
+func orderedOf(callable Callable) (ordered, bool) {
+	switch callable := callable.(type) {
+	case Value:
+		return callable.ordered, true
+	case multi:
+		return callable.ordered, true
+	case bounded:
+		return callable.ordered, true
+	case panicking:
+		return callable.ordered, true
+	}
+	return ordered{}, false
 }
 
 func orderedOption[T any](inOrder bool, options []Option[T]) Option[T] {
 	return func(key *T) {
-		mock := registry[key]
+		mock := lookup(key)
 		defer func(restore bool) {
 			mock.inOrder = restore
 		}(mock.inOrder)
@@ -25,3 +111,17 @@ func ExpectInOrder[T any](options ...Option[T]) Option[T] {
 func ExpectAnyOrder[T any](options ...Option[T]) Option[T] {
 	return orderedOption(false, options)
 }
+
+// ExpectInSequence is like ExpectInOrder, except that the ordinals of the
+// expectations are drawn from the given Sequence, so that calls are ordered
+// across all of the mocks that share it.
+func ExpectInSequence[T any](seq *Sequence, options ...Option[T]) Option[T] {
+	return func(key *T) {
+		mock := lookup(key)
+		defer func(restore *Sequence) {
+			mock.seq = restore
+		}(mock.seq)
+		mock.seq = seq
+		orderedOption(true, options)(key)
+	}
+}
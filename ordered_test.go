@@ -0,0 +1,118 @@
+package vermock_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestExpectInSequence(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		cacheFirst bool
+		expectFail bool
+	}{
+		{name: "in order", cacheFirst: true},
+		{name: "out of order", expectFail: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			seq := vermock.NewSequence()
+			cache := vermock.New(mockT,
+				vermock.ExpectInSequence(seq,
+					vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return nil, false }),
+				),
+			)
+			db := vermock.New(mockT,
+				vermock.ExpectInSequence(seq,
+					vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+				),
+			)
+			if tc.cacheFirst {
+				cache.Get("foo")
+				_ = db.Put("foo", "bar")
+			} else {
+				_ = db.Put("foo", "bar")
+				cache.Get("foo")
+			}
+			vermock.AssertExpectedCalls(mockT, cache, db)
+			if got := mockT.Failed(); got != tc.expectFail {
+				t.Errorf("expected failed to be %v, got %v", tc.expectFail, got)
+			}
+		})
+	}
+
+	t.Run("own sequence unaffected", func(t *testing.T) {
+		mockT := &testing.T{}
+		seq := vermock.NewSequence()
+		cache := vermock.New(mockT,
+			vermock.ExpectInSequence(seq,
+				vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return nil, false }),
+			),
+			vermock.ExpectInOrder(
+				vermock.Expect[mockCache]("Delete", func(key string) {}),
+			),
+		)
+		cache.Delete("foo")
+		cache.Get("foo")
+		vermock.AssertExpectedCalls(mockT, cache)
+		if mockT.Failed() {
+			t.Error("expected no failure")
+		}
+	})
+}
+
+func TestAssertExpectedCalls_unreachedOrdered(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		calls func(cache *mockCache)
+		want  string
+	}{
+		{
+			name: "last unreached",
+			calls: func(cache *mockCache) {
+				_ = cache.Put("foo", "bar")
+				cache.Get("foo")
+			},
+			want: "ordered call 3 to Delete was never reached: only got 2 ordered calls",
+		},
+		{
+			name:  "none reached",
+			calls: func(cache *mockCache) {},
+			want:  "ordered call 1 to Put was never reached: only got 0 ordered calls",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := vermock.New(t,
+				vermock.ExpectInOrder(
+					vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+					vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return nil, false }),
+					vermock.Expect[mockCache]("Delete", func(key string) {}),
+				),
+			)
+			tc.calls(cache)
+			defer func() {
+				r := recover()
+				if msg, ok := r.(string); !ok || !strings.Contains(msg, tc.want) {
+					t.Errorf("unexpected failure: %v", r)
+				}
+			}()
+			vermock.AssertExpectedCallsWith(t, []vermock.AssertOption{vermock.WithPanicOnFail()}, cache)
+		})
+	}
+}
+
+func TestExpectInOrder_outOfOrderNames(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.ExpectInOrder(
+			vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+			vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return nil, false }),
+		),
+	)
+	_, err := vermock.TryCall(cache, "Get", []reflect.Type{reflect.TypeOf((*any)(nil)).Elem(), reflect.TypeOf(true)}, reflect.ValueOf("foo"))
+	if want := "out of order: expected Put here but Get was called"; err == nil || err.Error() != want {
+		t.Errorf("unexpected error: expected %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,67 @@
+package vermock
+
+import (
+	"reflect"
+	"testing"
+)
+
+// panicking is a Callable that panics with a value.  Like a Value, it is
+// called once, and is ordered by ExpectInOrder.
+type panicking struct {
+	value   any
+	invoked *bool
+	ordered
+}
+
+// Call marks the Callable as invoked and panics.
+func (p panicking) Call(testing.TB, CallCount, []reflect.Value) []reflect.Value {
+	*p.invoked = true
+	panic(p.value)
+}
+
+// ExpectPanic registers a call to the method with the given name that panics
+// with the given value, which may be verified with AssertPanicked.  Within
+// ExpectInOrder, the call is ordered like one registered with Expect.
+func ExpectPanic[T any](name string, value any) Option[T] {
+	return func(key *T) {
+		mock := lookup(key)
+		mock.Helper()
+		delegateByName(mock, name).Append(panicking{
+			value:   value,
+			invoked: new(bool),
+			ordered: mock.next(name),
+		})
+	}
+}
+
+// AssertPanicked asserts that every call registered with ExpectPanic for the
+// method with the given name of the given mock was made, and so panicked.
+func AssertPanicked[T any](t testing.TB, key *T, name string) {
+	t.Helper()
+
+	mock := lookup(key)
+	if mock == nil {
+		t.Fatalf("mock not found: %T", key)
+	}
+
+	delegate, ok := existingDelegate(mock, name)
+	if !ok {
+		t.Errorf("no panic expected for %s", name)
+		return
+	}
+	delegate.Lock()
+	defer delegate.Unlock()
+	expected := false
+	for _, callable := range delegate.Callables {
+		if p, ok := callable.(panicking); ok {
+			expected = true
+			if !*p.invoked {
+				t.Errorf("failed to make call to %s: expected panic %v", name, p.value)
+				return
+			}
+		}
+	}
+	if !expected {
+		t.Errorf("no panic expected for %s", name)
+	}
+}
@@ -0,0 +1,46 @@
+package vermock_test
+
+import (
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestAssertPanicked(t *testing.T) {
+	t.Run("panicked", func(t *testing.T) {
+		cache := vermock.New(t, vermock.ExpectPanic[mockCache]("Delete", "boom"))
+		func() {
+			defer func() {
+				if r := recover(); r != "boom" {
+					t.Errorf("unexpected panic: %v", r)
+				}
+			}()
+			cache.Delete("foo")
+		}()
+		vermock.AssertPanicked(t, cache, "Delete")
+		vermock.AssertExpectedCalls(t, cache)
+	})
+
+	t.Run("not panicked", func(t *testing.T) {
+		mockT := &testing.T{}
+		cache := vermock.New(mockT, vermock.ExpectPanic[mockCache]("Delete", "boom"))
+		vermock.AssertPanicked(mockT, cache, "Delete")
+		if !mockT.Failed() {
+			t.Error("expected failure when the panic was not triggered")
+		}
+	})
+}
+
+func TestExpectPanic_inOrder(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.ExpectInOrder(
+			vermock.ExpectPanic[mockCache]("Delete", "boom"),
+			vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+		),
+	)
+	_ = cache.Put("foo", "bar")
+	if !mockT.Failed() {
+		t.Error("expected failure for call before the panic")
+	}
+}
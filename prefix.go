@@ -0,0 +1,99 @@
+package vermock
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExpectPrefix registers a function to be called for all calls of the methods
+// whose names start with the given prefix, and that have no expectations, or
+// other options such as Match or WithDefault, of their own.  When more than
+// one prefix matches, the longest is used.  Like ExpectMany, fn must be called
+// at least once, and its arguments must match the signature of each method
+// that it is called for, and may optionally be preceded by a testing.TB or
+// *testing.T and a CallCount, which counts the calls of all of the methods.
+// Panics if fn is not a function.
+func ExpectPrefix[T any](prefix string, fn any) Option[T] {
+	if funcType := reflect.TypeOf(fn); funcType == nil || funcType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("vermock.ExpectPrefix: expected function, got %T", fn))
+	}
+	return func(key *T) {
+		mock := lookup(key)
+		mock.Helper()
+		prefixDelegate(mock, prefix).Append(multi{
+			Value:   reflect.ValueOf(fn),
+			ordered: mock.next(prefix + "*"),
+		})
+	}
+}
+
+// prefixDelegate retrieves or creates the Delegate for the given prefix.
+func prefixDelegate(mock *mock, prefix string) *Delegate {
+	mock.Lock()
+	defer mock.Unlock()
+	if mock.prefixes == nil {
+		mock.prefixes = Delegates{}
+	}
+	delegate, ok := mock.prefixes[prefix]
+	if !ok {
+		delegate = new(Delegate)
+		mock.prefixes[prefix] = delegate
+	}
+	return delegate
+}
+
+// delegateFor returns the Delegate to call for the method with the given
+// name, as found by existingDelegate, or if there is none, a new Delegate of
+// the name.  The lock of the Delegate is not taken, as it is held for the
+// duration of a call.
+func delegateFor(mock *mock, name string) *Delegate {
+	if delegate, ok := existingDelegate(mock, name); ok {
+		return delegate
+	}
+	return delegateByName(mock, name)
+}
+
+// existingDelegate returns the Delegate of the method with the given name, or
+// false if there is none, without creating one.  The Delegate of the longest
+// prefix of the name registered with ExpectPrefix is returned instead, unless
+// the Delegate of the name has expectations, a default or matchers of its own,
+// so that a Delegate left empty, such as one created by a lookup or cleared by
+// ResetMethod, does not hide the prefix.
+func existingDelegate(mock *mock, name string) (*Delegate, bool) {
+	mock.Lock()
+	delegate, ok := mock.Delegates[name]
+	_, prefixed, hasPrefix := longestPrefix(mock, name)
+	mock.Unlock()
+	if hasPrefix && (!ok || delegate.empty()) {
+		return prefixed, true
+	}
+	return delegate, ok
+}
+
+// longestPrefix returns the longest of the prefixes of the mock that the given
+// name starts with, and its Delegate, or false if there is none.  The mock
+// must be locked.
+func longestPrefix(mock *mock, name string) (longest string, delegate *Delegate, ok bool) {
+	for prefix, d := range mock.prefixes {
+		if strings.HasPrefix(name, prefix) && (!ok || len(prefix) > len(longest)) {
+			longest, delegate, ok = prefix, d, true
+		}
+	}
+	return
+}
+
+// allDelegates returns a copy of the Delegates of the mock, together with the
+// Delegates of its prefixes, named by the prefix followed by "*".
+func allDelegates(mock *mock) Delegates {
+	mock.Lock()
+	defer mock.Unlock()
+	all := make(Delegates, len(mock.Delegates)+len(mock.prefixes))
+	for name, delegate := range mock.Delegates {
+		all[name] = delegate
+	}
+	for prefix, delegate := range mock.prefixes {
+		all[prefix+"*"] = delegate
+	}
+	return all
+}
@@ -0,0 +1,84 @@
+package vermock_test
+
+import (
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestExpectPrefix(t *testing.T) {
+	var got []string
+	cache := vermock.New(t,
+		vermock.ExpectPrefix[mockCache]("De", func(n vermock.CallCount, key string) {
+			got = append(got, "De "+key)
+		}),
+		vermock.ExpectPrefix[mockCache]("Del", func(n vermock.CallCount, key string) {
+			got = append(got, "Del "+key)
+		}),
+		vermock.ExpectPrefix[mockCache]("", func(key string) (any, bool) {
+			got = append(got, "* "+key)
+			return nil, false
+		}),
+		vermock.Expect[mockCache]("Put", func(key string, value any) error {
+			got = append(got, "Put "+key)
+			return nil
+		}),
+	)
+	cache.Delete("foo")
+	cache.Delete("bar")
+	cache.Get("baz")
+	_ = cache.Put("qux", nil)
+
+	want := []string{"Del foo", "Del bar", "* baz", "Put qux"}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected calls: expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unexpected call %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	mockT := &testing.T{}
+	vermock.AssertExpectedCalls(mockT, cache)
+	if !mockT.Failed() {
+		t.Error("expected failure for uncalled prefix De")
+	}
+}
+
+func TestExpectPrefix_exactFirst(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.ExpectPrefix[mockCache]("Delete", func(key string) {}),
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+	)
+	cache.Delete("foo")
+	// the exact delegate has no expectations left
+	cache.Delete("foo")
+	if !mockT.Failed() {
+		t.Error("expected failure for unexpected call")
+	}
+}
+
+func TestExpectPrefix_lookup(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.WithCallTimestamps[mockCache](),
+		vermock.ExpectPrefix[mockCache]("Get", func(key string) (any, bool) {
+			return 7, true
+		}),
+	)
+	if value, _ := cache.Get("foo"); value != 7 {
+		t.Errorf("unexpected result: expected 7, got %v", value)
+	}
+	if calls := vermock.SpyCalls(cache, "Get"); calls != nil {
+		t.Errorf("unexpected spy calls: %v", calls)
+	}
+	vermock.AssertCalledBefore(t, cache, "Get", func() {
+		cache.Get("bar")
+	})
+	// an empty Delegate of the name does not hide the prefix
+	vermock.ResetMethod(cache, "Get")
+	if value, _ := cache.Get("baz"); value != 7 {
+		t.Errorf("unexpected result: expected 7, got %v", value)
+	}
+}
@@ -0,0 +1,50 @@
+package vermock_test
+
+import (
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestReturn(t *testing.T) {
+	cache := vermock.New(t,
+		vermock.Return[mockCache]("Get", "bar", true),
+		vermock.Return[mockCache]("Get", nil, false),
+		vermock.Return[mockCache]("Put", nil),
+		vermock.Return[mockCache]("Load"),
+	)
+	if value, ok := cache.Get("foo"); value != "bar" || !ok {
+		t.Errorf("unexpected result: %v, %v", value, ok)
+	}
+	if value, ok := cache.Get("foo"); value != nil || ok {
+		t.Errorf("unexpected result: %v, %v", value, ok)
+	}
+	if err := cache.Put("foo", "bar"); err != nil {
+		t.Error("unexpected error:", err)
+	}
+	cache.Load("foo", "bar")
+	vermock.AssertExpectedCalls(t, cache)
+}
+
+func TestReturn_invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		method string
+		values []any
+		want   string
+	}{
+		{name: "not found", method: "Foo", want: "vermock.Return: method Foo not found for *vermock_test.mockCache"},
+		{name: "count", method: "Get", values: []any{"bar"}, want: "vermock.Return: unexpected number of results: expected 2, got 1"},
+		{name: "type", method: "Get", values: []any{"bar", 1}, want: "vermock.Return: unexpected type int for result parameter *bool"},
+		{name: "nil", method: "Get", values: []any{"bar", nil}, want: "vermock.Return: unexpected type <nil> for result parameter *bool"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.want {
+					t.Errorf("unexpected panic: %v", r)
+				}
+			}()
+			vermock.Return[mockCache](tc.method, tc.values...)
+		})
+	}
+}
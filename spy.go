@@ -0,0 +1,64 @@
+package vermock
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// spy is a MultiCallable that calls a real implementation of a method and
+// records the arguments of each call.  The arguments are recorded while the
+// lock of the Delegate is held.
+type spy struct {
+	multi
+	calls [][]any
+}
+
+// Call records the given arguments and invokes the real implementation.
+func (s *spy) Call(t testing.TB, i CallCount, in []reflect.Value) []reflect.Value {
+	s.calls = append(s.calls, fromValues(in))
+	return Value(s.multi).Call(t, i, in)
+}
+
+// Spy registers a real implementation of the method with the given name to be
+// called, with the same arguments and results, for all remaining calls of the
+// method.  Unlike ExpectMany, the method need not be called.  The arguments of
+// each call are recorded and returned by SpyCalls.  The calls are not ordered
+// by ExpectInOrder.
+// Panics if T has no method with the given name, or real is not a function
+// with the signature of the method.
+func Spy[T any](name string, real any) Option[T] {
+	funcType := methodFuncType[T]("vermock.Spy", name)
+	if reflect.TypeOf(real) != funcType {
+		panic(fmt.Sprintf("vermock.Spy: expected %s, got %T", funcType, real))
+	}
+	return func(key *T) {
+		mock := lookup(key)
+		mock.Helper()
+		delegateByName(mock, name).Append(&spy{
+			multi: multi{Value: reflect.ValueOf(real)},
+		})
+	}
+}
+
+// SpyCalls returns the arguments of each call of the method with the given
+// name that was made to a real implementation registered with Spy.  It
+// returns nil if the mock is not found.
+func SpyCalls[T any](key *T, name string) (calls [][]any) {
+	mock := lookup(key)
+	if mock == nil {
+		return nil
+	}
+	delegate, ok := existingDelegate(mock, name)
+	if !ok {
+		return nil
+	}
+	delegate.Lock()
+	defer delegate.Unlock()
+	for _, callable := range delegate.Callables {
+		if s, ok := callable.(*spy); ok {
+			calls = append(calls, s.calls...)
+		}
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package vermock_test
+
+import (
+	"reflect"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+// realCache is a trivial implementation of Cache to spy on.
+type realCache map[string]any
+
+func (c realCache) Put(key string, value any) error {
+	c[key] = value
+	return nil
+}
+
+func (c realCache) Get(key string) (any, bool) {
+	value, ok := c[key]
+	return value, ok
+}
+
+func (c realCache) Delete(key string) {
+	delete(c, key)
+}
+
+func (c realCache) Load(keys ...string) {}
+
+func TestSpy(t *testing.T) {
+	real := realCache{"foo": "bar"}
+	cache := vermock.New(t,
+		vermock.Spy[mockCache]("Get", real.Get),
+		vermock.Spy[mockCache]("Delete", real.Delete),
+	)
+
+	if value, ok := cache.Get("foo"); value != "bar" || !ok {
+		t.Errorf("unexpected result: %v, %v", value, ok)
+	}
+	if value, ok := cache.Get("baz"); value != nil || ok {
+		t.Errorf("unexpected result: %v, %v", value, ok)
+	}
+
+	if n := vermock.CallCountOf(cache, "Get"); n != 2 {
+		t.Errorf("expected 2 calls to Get, got %d", n)
+	}
+	if calls := vermock.SpyCalls(cache, "Get"); !reflect.DeepEqual(calls, [][]any{{"foo"}, {"baz"}}) {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	// Delete was not called, which is allowed for a spy
+	vermock.AssertExpectedCalls(t, cache)
+}
+
+func TestSpy_inOrder(t *testing.T) {
+	mockT := &testing.T{}
+	cache := vermock.New(mockT,
+		vermock.ExpectInOrder(
+			vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+			vermock.Spy[mockCache]("Get", realCache{}.Get),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		),
+	)
+	_ = cache.Put("foo", "bar")
+	cache.Get("foo")
+	cache.Delete("foo")
+	if mockT.Failed() {
+		t.Error("expected the spy to leave the order of the other calls intact")
+	}
+}
+
+func TestSpy_invalid(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "vermock.Spy: expected func(string) (interface {}, bool), got func(string)" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	vermock.Spy[mockCache]("Get", realCache{}.Delete)
+}
@@ -0,0 +1,50 @@
+package vermock
+
+import (
+	"fmt"
+	"math"
+)
+
+// Times returns an Option that applies o n times, such that
+// Expect[T](name, fn).Times(n) is equivalent to ExpectTimes[T](name, n, fn).
+// When n is 0 nothing is registered.
+// Panics if n is negative.
+func (o Option[T]) Times(n int) Option[T] {
+	if n < 0 {
+		panic(fmt.Sprintf("vermock.Option.Times: negative count %d", n))
+	}
+	return func(key *T) {
+		for i := 0; i < n; i++ {
+			o(key)
+		}
+	}
+}
+
+// AtLeast returns an Option that applies o, and then expects each call that
+// it registered to be made at least n times, such that
+// Expect[T](name, fn).AtLeast(1) is equivalent to ExpectMany[T](name, fn).
+// AssertExpectedCalls marks the test as failed when there are fewer than n
+// calls.  Like ExpectMany, it should be the last expectation for the method.
+// Panics if n is negative, or when applied, if o registers a call other than
+// with Expect.
+func (o Option[T]) AtLeast(n int) Option[T] {
+	if n < 0 {
+		panic(fmt.Sprintf("vermock.Option.AtLeast: negative count %d", n))
+	}
+	return func(key *T) {
+		for name, r := range register(key, o) {
+			r.delegate.Lock()
+			value, ok := r.delegate.last().(Value)
+			if !ok {
+				r.delegate.Unlock()
+				panic(fmt.Sprintf("vermock.Option.AtLeast: expected Expect of %s, got %T", name, r.delegate.last()))
+			}
+			r.delegate.Callables[r.delegate.Len()-1] = bounded{
+				multi: multi(value),
+				min:   n,
+				max:   math.MaxInt,
+			}
+			r.delegate.Unlock()
+		}
+	}
+}
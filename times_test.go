@@ -0,0 +1,89 @@
+package vermock_test
+
+import (
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestOption_Times(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		calls  int
+		failed bool
+	}{
+		{name: "too few", calls: 2, failed: true},
+		{name: "exact", calls: 3},
+		{name: "too many", calls: 4, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			cache := vermock.New(mockT,
+				vermock.Expect[mockCache]("Delete", func(key string) {}).Times(3),
+			)
+			for i := 0; i < tc.calls; i++ {
+				cache.Delete("foo")
+			}
+			vermock.AssertExpectedCalls(mockT, cache)
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+		})
+	}
+}
+
+func TestOption_AtLeast(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		min    int
+		calls  int
+		failed bool
+	}{
+		{name: "none uncalled", min: 0, calls: 0},
+		{name: "too few", min: 2, calls: 1, failed: true},
+		{name: "min", min: 2, calls: 2},
+		{name: "more", min: 2, calls: 5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			var counts []vermock.CallCount
+			cache := vermock.New(mockT,
+				vermock.Expect[mockCache]("Delete", func(n vermock.CallCount, key string) {
+					counts = append(counts, n)
+				}).AtLeast(tc.min),
+			)
+			for i := 0; i < tc.calls; i++ {
+				cache.Delete("foo")
+			}
+			vermock.AssertExpectedCalls(mockT, cache)
+			if got := mockT.Failed(); got != tc.failed {
+				t.Errorf("expected failed to be %v, got %v", tc.failed, got)
+			}
+			if len(counts) != tc.calls {
+				t.Errorf("expected %d calls of the delegate, got %d", tc.calls, len(counts))
+			}
+		})
+	}
+}
+
+func TestOption_AtLeast_notExpect(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	vermock.New(t,
+		vermock.ExpectMany[mockCache]("Delete", func(key string) {}).AtLeast(1),
+	)
+}
+
+func TestOption_AtLeast_prefix(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "vermock.Option.AtLeast: expected Expect of De*, got vermock.multi" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	vermock.New(t,
+		vermock.ExpectPrefix[mockCache]("De", func(key string) {}).AtLeast(1),
+	)
+}
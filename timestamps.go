@@ -0,0 +1,66 @@
+package vermock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// WithCallTimestamps records the time of each call to the mock's methods, so
+// that calls can be verified with AssertCalledBefore.  The time elapsed since
+// the option was applied, and since the previous call to any of the mock's
+// methods, is included in the log line of each call.
+func WithCallTimestamps[T any]() Option[T] {
+	return func(key *T) {
+		mock := lookup(key)
+		mock.timestamps = true
+		mock.logMu.Lock()
+		defer mock.logMu.Unlock()
+		mock.started = time.Now()
+	}
+}
+
+// timing records a call made at the given time and describes its timing for
+// the log line of the call.
+func (m *mock) timing(now time.Time) string {
+	m.logMu.Lock()
+	defer m.logMu.Unlock()
+	timing := fmt.Sprintf(" at +%s", now.Sub(m.started))
+	if !m.lastCall.IsZero() {
+		timing += fmt.Sprintf(", %s after previous call", now.Sub(m.lastCall))
+	}
+	m.lastCall = now
+	return timing
+}
+
+// AssertCalledBefore asserts that the method with the given name of the given
+// mock was called while marker ran, that is, before marker returns, as is
+// expected when the code under test calls the method synchronously.  A call
+// made before marker was invoked does not count.  The mock must be
+// constructed with WithCallTimestamps.
+func AssertCalledBefore[T any](t testing.TB, key *T, name string, marker func()) {
+	t.Helper()
+
+	mock := lookup(key)
+	if mock == nil {
+		t.Fatalf("mock not found: %T", key)
+	}
+	if !mock.timestamps {
+		t.Fatalf("call timestamps not recorded for %T: use WithCallTimestamps", key)
+	}
+
+	start := time.Now()
+	marker()
+	ref := time.Now()
+
+	if delegate, ok := existingDelegate(mock, name); ok {
+		delegate.Lock()
+		defer delegate.Unlock()
+		for _, at := range delegate.callTimes {
+			if !at.Before(start) && !at.After(ref) {
+				return
+			}
+		}
+	}
+	t.Errorf("failed to make call to %s while marker ran", name)
+}
@@ -0,0 +1,92 @@
+package vermock_test
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+func TestAssertCalledBefore(t *testing.T) {
+	t.Run("synchronous", func(t *testing.T) {
+		cache := vermock.New(t,
+			vermock.WithCallTimestamps[mockCache](),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		)
+		vermock.AssertCalledBefore(t, cache, "Delete", func() {
+			cache.Delete("foo")
+		})
+		vermock.AssertExpectedCalls(t, cache)
+	})
+
+	t.Run("asynchronous", func(t *testing.T) {
+		mockT := &testing.T{}
+		release := make(chan struct{})
+		var wg sync.WaitGroup
+		cache := vermock.New(mockT,
+			vermock.WithCallTimestamps[mockCache](),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		)
+		vermock.AssertCalledBefore(mockT, cache, "Delete", func() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-release
+				cache.Delete("foo")
+			}()
+		})
+		close(release)
+		wg.Wait()
+		if !mockT.Failed() {
+			t.Error("expected failure for asynchronous call")
+		}
+	})
+
+	t.Run("before marker", func(t *testing.T) {
+		mockT := &testing.T{}
+		cache := vermock.New(mockT,
+			vermock.WithCallTimestamps[mockCache](),
+			vermock.Expect[mockCache]("Delete", func(key string) {}),
+		)
+		cache.Delete("foo")
+		vermock.AssertCalledBefore(mockT, cache, "Delete", func() {})
+		if !mockT.Failed() {
+			t.Error("expected failure for call before marker")
+		}
+	})
+}
+
+// logT is a testing.TB that records its log lines.
+type logT struct {
+	testing.T
+	logs []string
+}
+
+func (t *logT) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func TestWithCallTimestamps_log(t *testing.T) {
+	mockT := &logT{}
+	cache := vermock.New(mockT,
+		vermock.WithCallTimestamps[mockCache](),
+		vermock.Expect[mockCache]("Put", func(key string, value any) error { return nil }),
+		vermock.Expect[mockCache]("Delete", func(key string) {}),
+	)
+	_ = cache.Put("foo", "bar")
+	cache.Delete("foo")
+	want := []*regexp.Regexp{
+		regexp.MustCompile(`^call to Put: 0/0 at \+\S+$`),
+		regexp.MustCompile(`^call to Delete: 0/0 at \+\S+, \S+ after previous call$`),
+	}
+	if len(mockT.logs) != len(want) {
+		t.Fatalf("expected %d log lines, got %q", len(want), mockT.logs)
+	}
+	for i, re := range want {
+		if !re.MatchString(mockT.logs[i]) {
+			t.Errorf("expected log line matching %s, got %q", re, mockT.logs[i])
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package vermock
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// NewValidated is like New, except that after applying the given options, it
+// verifies that the delegates registered for each method, including any
+// default, are compatible with the method of the same name of the interface
+// I, as with ExpectMethod, and that I has the method.  The delegate of a
+// prefix registered with ExpectPrefix must be compatible with each method of
+// I that it would be called for, of which there must be at least one.  A call
+// registered with ExpectPanic has no delegate, so only its method is checked.
+// The delegate of a variadic method may omit the variadic parameter.  Each
+// incompatibility is reported with t.Error, and then the test is stopped with
+// t.FailNow, rather than failing when the method is called.
+// Panics if I is not an interface type.
+func NewValidated[T, I any](t testing.TB, opts ...Option[T]) *T {
+	t.Helper()
+	ifaceType := reflect.TypeOf((*I)(nil)).Elem()
+	if ifaceType.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("vermock.NewValidated: expected interface type, got %s", ifaceType))
+	}
+	key := New(t, opts...)
+	if errs := validate(lookup(key), ifaceType); len(errs) > 0 {
+		for _, err := range errs {
+			t.Error(err)
+		}
+		t.FailNow()
+	}
+	return key
+}
+
+// validate returns an error for each delegate of the mock that is not
+// compatible with the method of the same name of the interface type, or for
+// a prefix, with each method of the interface type that it matches.
+func validate(mock *mock, ifaceType reflect.Type) (errs []error) {
+	names, delegates := sortedDelegates(mock)
+	for i, name := range names {
+		method, ok := ifaceType.MethodByName(name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("method %s not found for %s", name, ifaceType))
+			continue
+		}
+		errs = append(errs, validateDelegate(delegates[i], name, method.Type)...)
+	}
+
+	mock.Lock()
+	defer mock.Unlock()
+	matched := make(map[string]bool)
+	for i := 0; i < ifaceType.NumMethod(); i++ {
+		method := ifaceType.Method(i)
+		if _, ok := mock.Delegates[method.Name]; ok {
+			continue
+		}
+		if prefix, delegate, ok := longestPrefix(mock, method.Name); ok {
+			matched[prefix] = true
+			errs = append(errs, validateDelegate(delegate, prefix+"* for "+method.Name, method.Type)...)
+		}
+	}
+	prefixes := make([]string, 0, len(mock.prefixes))
+	for prefix := range mock.prefixes {
+		if !matched[prefix] {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		errs = append(errs, fmt.Errorf("no method of %s matches prefix %s", ifaceType, prefix))
+	}
+	return
+}
+
+// validateDelegate returns an error for each Callable of the delegate, as
+// described by validate, that is not compatible with a method of type
+// funcType.
+func validateDelegate(delegate *Delegate, name string, funcType reflect.Type) (errs []error) {
+	delegate.Lock()
+	callables := append(Callables{}, delegate.Callables...)
+	if delegate.fallback != nil {
+		callables = callables.Append(delegate.fallback)
+	}
+	delegate.Unlock()
+	for i, callable := range callables {
+		delegateType, ok := callableType(callable)
+		if !ok || validDelegate(delegateType, funcType) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("invalid delegate %d of %s: expected %s, got %s", i, name, funcType, delegateType))
+	}
+	return
+}
+
+// callableType returns the type of the function of the given Callable, or
+// false if it has none.
+func callableType(callable Callable) (reflect.Type, bool) {
+	switch callable := callable.(type) {
+	case Value:
+		return callable.Type(), true
+	case multi:
+		return callable.Type(), true
+	case bounded:
+		return callable.Type(), true
+	case *spy:
+		return callable.Type(), true
+	}
+	return nil, false
+}
+
+// validDelegate reports whether a delegate of type delegateType may be called
+// for a method of type funcType, as described by delegateMatches, or by
+// omitting the variadic parameter of a variadic method.
+func validDelegate(delegateType, funcType reflect.Type) bool {
+	if delegateMatches(delegateType, funcType) {
+		return true
+	}
+	if !funcType.IsVariadic() {
+		return false
+	}
+	in := make([]reflect.Type, funcType.NumIn()-1)
+	for i := range in {
+		in[i] = funcType.In(i)
+	}
+	out := make([]reflect.Type, funcType.NumOut())
+	for i := range out {
+		out[i] = funcType.Out(i)
+	}
+	return delegateMatches(delegateType, reflect.FuncOf(in, out, false))
+}
@@ -0,0 +1,114 @@
+package vermock_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	vermock "github.com/Versent/go-vermock"
+)
+
+// errorT is a testing.TB that records its errors.
+type errorT struct {
+	testing.T
+	errors []string
+}
+
+func (t *errorT) Error(args ...any) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+	t.T.Fail()
+}
+
+func TestNewValidated(t *testing.T) {
+	cache := vermock.NewValidated[mockCache, Cache](t,
+		vermock.Expect[mockCache]("Put", func(t testing.TB, key string, value any) error {
+			return nil
+		}),
+		vermock.ExpectMany[mockCache]("Get", func(n vermock.CallCount, key string) (any, bool) {
+			return "bar", true
+		}),
+		vermock.Expect[mockCache]("Load", func() {}),
+		vermock.Expect[mockCache]("Load", func(keys ...string) {}),
+		vermock.WithDefault[mockCache]("Delete", func(key string) {}),
+	)
+	_ = cache.Put("foo", "bar")
+	cache.Get("foo")
+	cache.Load()
+	cache.Load("foo", "bar")
+}
+
+func TestNewValidated_prefix(t *testing.T) {
+	cache := vermock.NewValidated[mockCache, Cache](t,
+		vermock.Expect[mockCache]("Get", func(key string) (any, bool) { return nil, false }),
+		vermock.ExpectPrefix[mockCache]("De", func(key string) {}),
+	)
+	cache.Get("foo")
+	cache.Delete("foo")
+}
+
+func TestNewValidated_invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opt  vermock.Option[mockCache]
+		want string
+	}{
+		{
+			name: "wrong parameter",
+			opt:  vermock.Expect[mockCache]("Delete", func(key int) {}),
+			want: "invalid delegate 0 of Delete: expected func(string), got func(int)",
+		},
+		{
+			name: "wrong result",
+			opt: vermock.ExpectMany[mockCache]("Get", func(key string) any {
+				return nil
+			}),
+			want: "invalid delegate 0 of Get: expected func(string) (interface {}, bool), got func(string) interface {}",
+		},
+		{
+			name: "wrong default",
+			opt:  vermock.WithDefault[mockCache]("Delete", func() {}),
+			want: "invalid delegate 0 of Delete: expected func(string), got func()",
+		},
+		{
+			name: "unknown method",
+			opt:  vermock.Expect[mockCache]("Clear", func() {}),
+			want: "method Clear not found for vermock_test.Cache",
+		},
+		{
+			name: "wrong prefix",
+			opt:  vermock.ExpectPrefix[mockCache]("Ge", func(key string) {}),
+			want: "invalid delegate 0 of Ge* for Get: expected func(string) (interface {}, bool), got func(string)",
+		},
+		{
+			name: "unmatched prefix",
+			opt:  vermock.ExpectPrefix[mockCache]("Clear", func() {}),
+			want: "no method of vermock_test.Cache matches prefix Clear",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockT := &errorT{}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				vermock.NewValidated[mockCache, Cache](mockT, tc.opt)
+				t.Error("expected FailNow")
+			}()
+			<-done
+			if !mockT.Failed() {
+				t.Error("expected failure")
+			}
+			if errs := strings.Join(mockT.errors, "\n"); !strings.Contains(errs, tc.want) {
+				t.Errorf("expected %q in %q", tc.want, errs)
+			}
+		})
+	}
+}
+
+func TestNewValidated_notInterface(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	vermock.NewValidated[mockCache, mockCache](t)
+}